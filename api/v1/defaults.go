@@ -0,0 +1,34 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// DefaultFooValue is the default of MyChildResourceSpec.FooValueDefault.
+const DefaultFooValue = "ho-ho-ho"
+
+// SetDefaults sets the defaults the API server applies from the
+// +kubebuilder:default markers of MyChildResourceSpec, so that children built
+// in Go compare equal to the children read back from the server. It is shared
+// by the defaulting webhook and the reconciler and must be kept in sync with
+// the markers.
+func (s *MyChildResourceSpec) SetDefaults() {
+	if s.FooMap == nil {
+		s.FooMap = map[string]string{}
+	}
+	if s.FooValueDefault == "" {
+		s.FooValueDefault = DefaultFooValue
+	}
+}
@@ -30,9 +30,17 @@ type MyChildResourceSpec struct {
 
 	// Foo is an example field of MyChildResource. Edit mychildresource_types.go to remove/update
 	Foo string `json:"foo,omitempty"`
+	// FooMap keys are label-style qualified names with an optional DNS
+	// subdomain prefix.
 	// +kubebuilder:default={}
-	FooMap  map[string]string `json:"fooMap,omitempty"`
-	FooList []string          `json:"fooList,omitempty"`
+	// +kubebuilder:validation:MaxProperties=64
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k.matches('^([a-z0-9]([-a-z0-9]*[a-z0-9])?([.][a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$'))",message="fooMap keys must be qualified names of at most 63 characters with an optional DNS subdomain prefix"
+	FooMap map[string]string `json:"fooMap,omitempty"`
+	// FooList entries are unique.
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:items:MaxLength=63
+	// +kubebuilder:validation:XValidation:rule="self.all(x, self.exists_one(y, y == x))",message="fooList entries must be unique"
+	FooList []string `json:"fooList,omitempty"`
 	// +kubebuilder:default="ho-ho-ho"
 	FooValueDefault string `json:"fooValueDefault,omitempty"`
 }
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	samplev2 "k8s-controller.ad/api/v2"
+)
+
+// ConvertTo converts this MyResource (v1) to the Hub version (v2).
+func (src *MyResource) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*samplev2.MyResource)
+	if !ok {
+		return fmt.Errorf("expected a v2 MyResource but got %T", dstRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = samplev2.MyResourceSpec{
+		Foo:      src.Spec.Foo,
+		Strategy: samplev2.Strategy{Type: samplev2.ApplyStrategy(src.Spec.Strategy)},
+		Rollout: samplev2.RolloutPolicy{
+			RevisionHistoryLimit: src.Spec.RevisionHistoryLimit,
+			RollbackTo:           (*samplev2.RollbackConfig)(src.Spec.RollbackTo),
+			Prune:                (*samplev2.Prune)(src.Spec.Prune),
+		},
+	}
+	if src.Spec.Children != nil {
+		dst.Spec.Children = make([]samplev2.ChildTemplate, 0, len(src.Spec.Children))
+		for _, tmpl := range src.Spec.Children {
+			dst.Spec.Children = append(dst.Spec.Children, samplev2.ChildTemplate{
+				Metadata: samplev2.ChildMetadata{
+					Name:        tmpl.Name,
+					Namespace:   tmpl.Namespace,
+					Labels:      tmpl.Labels,
+					Annotations: tmpl.Annotations,
+				},
+				Cluster:  tmpl.Cluster,
+				Strategy: samplev2.Strategy{Type: samplev2.ApplyStrategy(tmpl.Strategy)},
+				Spec:     samplev2.ChildSpec(tmpl.Spec),
+			})
+		}
+	}
+	if adoption := src.Spec.Adoption; adoption != nil {
+		dst.Spec.Adoption = &samplev2.Adoption{
+			Policy:   samplev2.AdoptionPolicy(adoption.Policy),
+			Selector: adoption.Selector,
+		}
+	}
+
+	dst.Status = samplev2.MyResourceStatus{
+		ObservedGeneration: src.Status.ObservedGeneration,
+		CurrentRevision:    src.Status.CurrentRevision,
+		Revision:           src.Status.Revision,
+		Conditions:         src.Status.Conditions,
+	}
+	if src.Status.Inventory != nil {
+		dst.Status.Inventory = make([]samplev2.ChildReference, 0, len(src.Status.Inventory))
+		for _, ref := range src.Status.Inventory {
+			dst.Status.Inventory = append(dst.Status.Inventory, samplev2.ChildReference(ref))
+		}
+	}
+	if src.Status.Clusters != nil {
+		dst.Status.Clusters = make([]samplev2.ClusterStatus, 0, len(src.Status.Clusters))
+		for _, cluster := range src.Status.Clusters {
+			dst.Status.Clusters = append(dst.Status.Clusters, samplev2.ClusterStatus(cluster))
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v2) to this version.
+func (dst *MyResource) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*samplev2.MyResource)
+	if !ok {
+		return fmt.Errorf("expected a v2 MyResource but got %T", srcRaw)
+	}
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = MyResourceSpec{
+		Foo:                  src.Spec.Foo,
+		Strategy:             ApplyStrategy(src.Spec.Strategy.Type),
+		RevisionHistoryLimit: src.Spec.Rollout.RevisionHistoryLimit,
+		RollbackTo:           (*RollbackConfig)(src.Spec.Rollout.RollbackTo),
+		Prune:                (*Prune)(src.Spec.Rollout.Prune),
+	}
+	if src.Spec.Children != nil {
+		dst.Spec.Children = make([]ChildTemplate, 0, len(src.Spec.Children))
+		for _, tmpl := range src.Spec.Children {
+			dst.Spec.Children = append(dst.Spec.Children, ChildTemplate{
+				Name:        tmpl.Metadata.Name,
+				Namespace:   tmpl.Metadata.Namespace,
+				Labels:      tmpl.Metadata.Labels,
+				Annotations: tmpl.Metadata.Annotations,
+				Strategy:    ApplyStrategy(tmpl.Strategy.Type),
+				Cluster:     tmpl.Cluster,
+				Spec:        MyChildResourceSpec(tmpl.Spec),
+			})
+		}
+	}
+	if adoption := src.Spec.Adoption; adoption != nil {
+		dst.Spec.Adoption = &Adoption{
+			Policy:   AdoptionPolicy(adoption.Policy),
+			Selector: adoption.Selector,
+		}
+	}
+
+	dst.Status = MyResourceStatus{
+		ObservedGeneration: src.Status.ObservedGeneration,
+		CurrentRevision:    src.Status.CurrentRevision,
+		Revision:           src.Status.Revision,
+		Conditions:         src.Status.Conditions,
+	}
+	if src.Status.Inventory != nil {
+		dst.Status.Inventory = make([]ChildReference, 0, len(src.Status.Inventory))
+		for _, ref := range src.Status.Inventory {
+			dst.Status.Inventory = append(dst.Status.Inventory, ChildReference(ref))
+		}
+	}
+	if src.Status.Clusters != nil {
+		dst.Status.Clusters = make([]ClusterStatus, 0, len(src.Status.Clusters))
+		for _, cluster := range src.Status.Clusters {
+			dst.Status.Clusters = append(dst.Status.Clusters, ClusterStatus(cluster))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"math/rand"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	samplev2 "k8s-controller.ad/api/v2"
+)
+
+// fuzzIterations is how many random objects each round trip converts.
+const fuzzIterations = 1000
+
+var _ = Describe("MyResource conversion", func() {
+	var fuzz interface{ Fuzz(any) }
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		Expect(samplev2.AddToScheme(scheme)).To(Succeed())
+		fuzz = fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(GinkgoRandomSeed()), serializer.NewCodecFactory(scheme))
+	})
+
+	It("Should round-trip v1 through the v2 hub without loss", func() {
+		for range fuzzIterations {
+			original := &MyResource{}
+			fuzz.Fuzz(original)
+			original.TypeMeta = metav1.TypeMeta{}
+
+			hub := &samplev2.MyResource{}
+			Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+			converted := &MyResource{}
+			Expect(converted.ConvertFrom(hub)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(original, converted)).To(BeTrue(),
+				"v1 -> v2 -> v1 changed the object:\n%s", cmp.Diff(original, converted))
+		}
+	})
+
+	It("Should round-trip the v2 hub through v1 without loss", func() {
+		for range fuzzIterations {
+			original := &samplev2.MyResource{}
+			fuzz.Fuzz(original)
+			original.TypeMeta = metav1.TypeMeta{}
+
+			spoke := &MyResource{}
+			Expect(spoke.ConvertFrom(original.DeepCopy())).To(Succeed())
+			converted := &samplev2.MyResource{}
+			Expect(spoke.ConvertTo(converted)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(original, converted)).To(BeTrue(),
+				"v2 -> v1 -> v2 changed the object:\n%s", cmp.Diff(original, converted))
+		}
+	})
+})
@@ -0,0 +1,250 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyStrategy names the way a rendered child is written to the cluster.
+// +kubebuilder:validation:Enum=SSA;Update;Replace;Patch;Suggested;ThreeWayMerge
+type ApplyStrategy string
+
+const (
+	// ApplyStrategySSA creates the child if needed and then server-side applies it.
+	ApplyStrategySSA ApplyStrategy = "SSA"
+	// ApplyStrategyUpdate coalesces the child over the live object and updates it.
+	ApplyStrategyUpdate ApplyStrategy = "Update"
+	// ApplyStrategyReplace overwrites labels, annotations and spec of the live object.
+	ApplyStrategyReplace ApplyStrategy = "Replace"
+	// ApplyStrategyPatch coalesces the child over the live object and patches it.
+	ApplyStrategyPatch ApplyStrategy = "Patch"
+	// ApplyStrategySuggested server-side applies the child without status and creationTimestamp.
+	ApplyStrategySuggested ApplyStrategy = "Suggested"
+	// ApplyStrategyThreeWayMerge patches the child with a three-way JSON merge
+	// patch between the last applied child, the rendered child and the live
+	// object. It requires the ThreeWayMerge feature gate.
+	ApplyStrategyThreeWayMerge ApplyStrategy = "ThreeWayMerge"
+)
+
+// Strategy configures how children are written to the cluster.
+type Strategy struct {
+	// Type is the apply strategy.
+	Type ApplyStrategy `json:"type,omitempty"`
+}
+
+// ChildMetadata is the metadata of a rendered child.
+type ChildMetadata struct {
+	// Name is the name of the rendered child.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+	// Namespace is the namespace of the rendered child, defaulting to the
+	// namespace of the MyResource. Other namespaces must be allowed by the
+	// controller's namespace allow-list.
+	Namespace string `json:"namespace,omitempty"`
+	// Labels are set on the rendered child.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are set on the rendered child.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ChildSpec is the spec of a rendered MyChildResource.
+type ChildSpec struct {
+	// Foo is an example field of MyChildResource.
+	Foo string `json:"foo,omitempty"`
+	// FooMap keys are label-style qualified names with an optional DNS
+	// subdomain prefix.
+	// +kubebuilder:default={}
+	// +kubebuilder:validation:MaxProperties=64
+	// +kubebuilder:validation:XValidation:rule="self.all(k, k.matches('^([a-z0-9]([-a-z0-9]*[a-z0-9])?([.][a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$'))",message="fooMap keys must be qualified names of at most 63 characters with an optional DNS subdomain prefix"
+	FooMap map[string]string `json:"fooMap,omitempty"`
+	// FooList entries are unique.
+	// +kubebuilder:validation:MaxItems=32
+	// +kubebuilder:validation:items:MaxLength=63
+	// +kubebuilder:validation:XValidation:rule="self.all(x, self.exists_one(y, y == x))",message="fooList entries must be unique"
+	FooList []string `json:"fooList,omitempty"`
+	// +kubebuilder:default="ho-ho-ho"
+	FooValueDefault string `json:"fooValueDefault,omitempty"`
+}
+
+// ChildTemplate describes a MyChildResource rendered from the parent.
+type ChildTemplate struct {
+	// Metadata is the metadata of the rendered child.
+	Metadata ChildMetadata `json:"metadata"`
+	// Cluster is the name of a Secret in the namespace of the MyResource whose
+	// kubeconfig key holds the kubeconfig of the remote cluster the child is
+	// applied to. Children without a cluster are applied to the local cluster.
+	// The kubeconfig may only hold inline credentials: token,
+	// client-certificate-data, client-key-data and certificate-authority-data.
+	Cluster string `json:"cluster,omitempty"`
+	// Strategy overrides spec.strategy for this child.
+	Strategy Strategy `json:"strategy,omitempty"`
+	// Spec is the spec of the rendered child.
+	Spec ChildSpec `json:"spec,omitempty"`
+}
+
+// AdoptionPolicy decides which existing children without a controller a
+// MyResource takes over.
+// +kubebuilder:validation:Enum=Never;MatchingSelector;Always
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyNever refuses to manage children the resource did not create.
+	AdoptionPolicyNever AdoptionPolicy = "Never"
+	// AdoptionPolicyMatchingSelector adopts children matching the adoption selector.
+	AdoptionPolicyMatchingSelector AdoptionPolicy = "MatchingSelector"
+	// AdoptionPolicyAlways adopts every child without a controller.
+	AdoptionPolicyAlways AdoptionPolicy = "Always"
+)
+
+// Adoption configures how existing children are claimed.
+type Adoption struct {
+	// Policy decides which children without a controller are adopted.
+	// +kubebuilder:default=Never
+	Policy AdoptionPolicy `json:"policy,omitempty"`
+	// Selector matches the children adopted with the MatchingSelector policy.
+	// Children that stop matching it are released.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// Prune configures the deletion of children that are no longer desired.
+type Prune struct {
+	// PropagationPolicy is used when deleting children that are no longer desired.
+	// +kubebuilder:validation:Enum=Background;Foreground;Orphan
+	// +kubebuilder:default=Background
+	PropagationPolicy metav1.DeletionPropagation `json:"propagationPolicy,omitempty"`
+}
+
+// RollbackConfig points at a previously recorded revision.
+type RollbackConfig struct {
+	// Revision is the number of the revision to apply.
+	// +kubebuilder:validation:Minimum=1
+	Revision int64 `json:"revision"`
+}
+
+// RolloutPolicy configures how changes of the child set are recorded, rolled
+// back and cleaned up.
+type RolloutPolicy struct {
+	// RevisionHistoryLimit is the number of old revisions kept for rollback.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// RollbackTo makes the controller apply the child set of a recorded revision
+	// instead of spec.children for as long as it is set.
+	RollbackTo *RollbackConfig `json:"rollbackTo,omitempty"`
+	// Prune configures how children removed from the desired child set are deleted.
+	// Children annotated with sample.k8s-controller.ad/prune=false are never deleted.
+	Prune *Prune `json:"prune,omitempty"`
+}
+
+// ChildReference identifies a child applied by a MyResource.
+type ChildReference struct {
+	// Cluster is the cluster Secret of a child in a remote cluster.
+	Cluster string `json:"cluster,omitempty"`
+	// Namespace of the child.
+	Namespace string `json:"namespace"`
+	// Name of the child.
+	Name string `json:"name"`
+}
+
+// ClusterStatus is the sync state of the children applied to a remote cluster.
+type ClusterStatus struct {
+	// Name is the name of the cluster Secret.
+	Name string `json:"name"`
+	// Synced is true when every child of the cluster was applied.
+	Synced bool `json:"synced"`
+	// Children is the number of children applied to the cluster.
+	Children int32 `json:"children"`
+	// Message explains why the cluster is not synced.
+	Message string `json:"message,omitempty"`
+	// LastSyncTime is the last time the cluster became synced.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// MyResourceSpec defines the desired state of MyResource.
+// +kubebuilder:validation:XValidation:rule="(has(self.strategy) && has(self.strategy.type) ? self.strategy.type : '') == (has(oldSelf.strategy) && has(oldSelf.strategy.type) ? oldSelf.strategy.type : '')",fieldPath=".strategy.type",message="strategy.type is immutable, set the strategy of child templates instead"
+type MyResourceSpec struct {
+	// Foo is an example field of MyResource.
+	Foo string `json:"foo,omitempty"`
+	// Strategy is the apply strategy of children that do not set their own,
+	// the default strategy of the manager when its type is empty. Its type
+	// cannot be set, changed or removed after creation: switching every child
+	// at once would leave their fields owned by the field managers of the
+	// previous strategy. Set the strategy of individual child templates
+	// instead.
+	Strategy Strategy `json:"strategy,omitempty"`
+	// Children are the templates of the MyChildResource objects owned by this
+	// resource, at most 100.
+	// +kubebuilder:validation:MaxItems=100
+	Children []ChildTemplate `json:"children,omitempty"`
+	// Adoption configures the adoption of existing children that were not
+	// created by this resource. Such children are left alone by default.
+	Adoption *Adoption `json:"adoption,omitempty"`
+	// Rollout configures the revisions of the child set and the pruning of
+	// children that are no longer desired.
+	// +kubebuilder:default={}
+	Rollout RolloutPolicy `json:"rollout,omitempty"`
+}
+
+// MyResourceStatus defines the observed state of MyResource.
+type MyResourceStatus struct {
+	// ObservedGeneration is the generation last handled by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// CurrentRevision is the name of the revision whose children were last applied.
+	CurrentRevision string `json:"currentRevision,omitempty"`
+	// Revision is the number of CurrentRevision.
+	Revision int64 `json:"revision,omitempty"`
+	// Inventory lists the children applied by the controller. Children that
+	// drop out of the desired child set are found and pruned through it.
+	Inventory []ChildReference `json:"inventory,omitempty"`
+	// Clusters reports the sync state of every remote cluster children are
+	// applied to.
+	// +listType=map
+	// +listMapKey=name
+	Clusters []ClusterStatus `json:"clusters,omitempty"`
+	// Conditions describe the state of the resource.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// MyResource is the Schema for the myresources API.
+type MyResource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MyResourceSpec   `json:"spec,omitempty"`
+	Status MyResourceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MyResourceList contains a list of MyResource.
+type MyResourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MyResource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MyResource{}, &MyResourceList{})
+}
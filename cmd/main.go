@@ -17,20 +17,33 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"flag"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -38,7 +51,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	samplev1 "k8s-controller.ad/api/v1"
+	samplev2 "k8s-controller.ad/api/v2"
+	"k8s-controller.ad/internal/accounting"
+	"k8s-controller.ad/internal/config"
 	"k8s-controller.ad/internal/controller"
+	"k8s-controller.ad/internal/features"
+	"k8s-controller.ad/internal/sharding"
+	"k8s-controller.ad/internal/tracing"
+	webhooksamplev1 "k8s-controller.ad/internal/webhook/v1"
+	webhooksamplev2 "k8s-controller.ad/internal/webhook/v2"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -47,15 +68,31 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+const leaderElectionID = "ffc60bcd.k8s-controller.ad"
+
+// subcommands run instead of the manager when named by the first argument.
+// Each returns the exit code of the process.
+var subcommands = map[string]func(args []string) int{
+	"migrate-storage": migrateStorage,
+	"render":          renderChildren,
+}
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(samplev1.AddToScheme(scheme))
+	utilruntime.Must(samplev2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 // nolint:gocyclo
 func main() {
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:]))
+		}
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
@@ -63,6 +100,19 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var childNamespaceAllowList string
+	var configFile string
+	var concurrency controller.ConcurrencyOptions
+	var enableEventFilters bool
+	var reconcileOnLabelKeys, reconcileOnAnnotationKeys string
+	var watchNamespaces, watchLabelSelector string
+	var tracingOpts tracing.Options
+	var defaultStrategy string
+	var resyncInterval time.Duration
+	var stallTimeout time.Duration
+	var enableSharding bool
+	var fieldProtectionBypassUsers string
+	featureGate := features.NewFeatureGate()
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -81,14 +131,112 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&childNamespaceAllowList, "child-namespace-allowlist", "",
+		"Namespaces children may be created in besides the namespace of their parent, "+
+			"as parent-ns=child-ns[,child-ns];... Use * for every parent or child namespace.")
+	flag.StringVar(&configFile, "config", "",
+		"The manager configuration file. Flags set on the command line take precedence over its values. "+
+			"The log level and the resync interval are reloaded when the file changes.")
+	flag.IntVar(&concurrency.MaxConcurrentReconciles, "max-concurrent-reconciles",
+		controller.DefaultMaxConcurrentReconciles, "The number of MyResources reconciled in parallel.")
+	flag.DurationVar(&concurrency.BackoffBase, "reconcile-backoff-base", controller.DefaultBackoffBase,
+		"The first per-item delay after a failed reconcile.")
+	flag.DurationVar(&concurrency.BackoffMax, "reconcile-backoff-max", controller.DefaultBackoffMax,
+		"The maximum per-item delay after repeated failed reconciles.")
+	flag.Float64Var(&concurrency.QPS, "reconcile-qps", controller.DefaultQPS,
+		"The overall rate of reconcile requeues allowed by the bucket rate limiter.")
+	flag.IntVar(&concurrency.Burst, "reconcile-burst", controller.DefaultBurst,
+		"The bucket size of the bucket rate limiter.")
+	flag.BoolVar(&enableEventFilters, "event-filters", true,
+		"If set, MyResource updates not changing the generation and status-only MyChildResource updates "+
+			"do not trigger a reconcile.")
+	flag.StringVar(&reconcileOnLabelKeys, "reconcile-on-label-keys", "",
+		"Comma-separated MyResource label keys whose changes trigger a reconcile with --event-filters.")
+	flag.StringVar(&reconcileOnAnnotationKeys, "reconcile-on-annotation-keys", "",
+		"Comma-separated MyResource annotation keys whose changes trigger a reconcile with --event-filters.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma-separated namespaces watched by the manager, all namespaces when empty. "+
+			"Must include the namespaces children are allowed in.")
+	flag.StringVar(&watchLabelSelector, "watch-label-selector", "",
+		"Label selector of the MyResources watched by the manager, all of them when empty.")
+	flag.StringVar(&tracingOpts.Endpoint, "otlp-endpoint", "",
+		"The host:port of the OTLP gRPC receiver traces are exported to. Tracing is disabled when empty.")
+	flag.BoolVar(&tracingOpts.Insecure, "otlp-insecure", false,
+		"If set, traces are exported to the OTLP receiver without TLS.")
+	flag.Float64Var(&tracingOpts.SampleRatio, "trace-sample-ratio", 1,
+		"The ratio of reconciles traced, between 0 and 1.")
+	flag.StringVar(&defaultStrategy, "default-strategy", string(samplev1.ApplyStrategySuggested),
+		"The apply strategy of children whose parent and template set none.")
+	flag.DurationVar(&resyncInterval, "resync-interval", controller.DefaultResyncInterval,
+		"The delay after which a reconciled MyResource is reconciled again.")
+	flag.DurationVar(&stallTimeout, "reconcile-stall-timeout", controller.DefaultStallTimeout,
+		"The time after which a reconcile still running, or queued requests no reconcile completed for, "+
+			"fail the liveness check. 0 disables the check.")
+	flag.BoolVar(&enableSharding, "sharding", false,
+		"If set, MyResources are spread across the replicas of the manager by consistent hashing instead of "+
+			"being reconciled by the leader only. Cannot be combined with --leader-elect.")
+	flag.StringVar(&fieldProtectionBypassUsers, "field-protection-bypass-users", "",
+		"Comma-separated list of users allowed to change the child fields applied by the controller while the "+
+			"ProtectChildFields feature is enabled, e.g. system:serviceaccount:<namespace>:<name>.")
+	flag.Var(features.Flag(featureGate), "feature-gates", "A set of key=value pairs that enable or disable features. Options are:\n"+
+		strings.Join(featureGate.KnownFeatures(), "\n"))
+	// The level is atomic so that it can be reloaded from the configuration
+	// file, unless --zap-log-level replaces it.
+	logLevel := uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
 	opts := zap.Options{
-		Development: true,
+		Level: logLevel,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	set := setFlags()
+	var cfg *config.Config
+	var cfgErr error
+	if len(configFile) > 0 {
+		if cfg, cfgErr = config.Load(configFile); cfgErr == nil {
+			cfgErr = applyConfig(cfg, logLevel, set)
+		}
+	}
+
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if cfgErr != nil {
+		setupLog.Error(cfgErr, "unable to load the configuration file")
+		os.Exit(1)
+	}
+	if !slices.Contains(samplev1.ApplyStrategies, samplev1.ApplyStrategy(defaultStrategy)) {
+		setupLog.Error(nil, "invalid default strategy", "strategy", defaultStrategy)
+		os.Exit(1)
+	}
+	if enableSharding && enableLeaderElection {
+		setupLog.Error(nil, "--sharding and --leader-elect are mutually exclusive")
+		os.Exit(1)
+	}
+	resync := controller.NewResync(resyncInterval)
+	watchdog := controller.NewWatchdog(stallTimeout)
+	features.RecordMetrics(featureGate)
+
+	namespaceAllowList, err := controller.ParseNamespaceAllowList(childNamespaceAllowList)
+	if err != nil {
+		setupLog.Error(err, "invalid child namespace allow-list")
+		os.Exit(1)
+	}
+
+	tracingOpts.ServiceName = "k8s-controller-simple"
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	cacheScope := controller.CacheScope{Namespaces: splitList(watchNamespaces)}
+	if len(watchLabelSelector) > 0 {
+		if cacheScope.LabelSelector, err = labels.Parse(watchLabelSelector); err != nil {
+			setupLog.Error(err, "invalid watch label selector")
+			os.Exit(1)
+		}
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -180,11 +328,12 @@ func main() {
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  controller.CacheOptions(cacheScope),
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "ffc60bcd.k8s-controller.ad",
+		LeaderElectionID:       leaderElectionID,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -202,15 +351,91 @@ func main() {
 		os.Exit(1)
 	}
 
+	var clusters *controller.ClusterPool
+	if featureGate.Enabled(features.RemoteClusters) {
+		clusters = controller.NewClusterPool(mgr.GetAPIReader(), mgr.GetScheme())
+		if err := mgr.Add(clusters); err != nil {
+			setupLog.Error(err, "unable to set up the remote cluster pool")
+			os.Exit(1)
+		}
+	}
+
+	var shard *sharding.Shard
+	if enableSharding {
+		if shard, err = setupShard(mgr); err != nil {
+			setupLog.Error(err, "unable to set up sharding")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controller.MyResourceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:             accounting.NewCachedClient(tracing.NewClient(mgr.GetClient())),
+		Scheme:             mgr.GetScheme(),
+		NamespaceAllowList: namespaceAllowList,
+		Concurrency:        concurrency,
+		EventFilters: controller.EventFilterOptions{
+			Disabled:       !enableEventFilters,
+			LabelKeys:      splitList(reconcileOnLabelKeys),
+			AnnotationKeys: splitList(reconcileOnAnnotationKeys),
+		},
+		Recorder:        mgr.GetEventRecorderFor("myresource-controller"),
+		Clusters:        clusters,
+		DefaultStrategy: samplev1.ApplyStrategy(defaultStrategy),
+		Resync:          resync,
+		Features:        featureGate,
+		Watchdog:        watchdog,
+		Shard:           shard,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MyResource")
 		os.Exit(1)
 	}
+	// serveWebhooks is false when running the manager locally without
+	// webhook certificates.
+	// nolint:goconst
+	serveWebhooks := os.Getenv("ENABLE_WEBHOOKS") != "false"
+	if serveWebhooks {
+		webhookOpts := webhooksamplev1.Options{
+			NamespaceAllowList:         namespaceAllowList,
+			Features:                   featureGate,
+			FieldProtectionBypassUsers: splitList(fieldProtectionBypassUsers),
+		}
+		if webhookOpts.ControllerUsername, err = controllerUsername(context.Background(), mgr); err != nil {
+			setupLog.Error(err, "unable to resolve the user of the controller")
+			os.Exit(1)
+		}
+		if err = webhooksamplev1.SetupMyResourceWebhookWithManager(mgr, webhookOpts); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MyResource")
+			os.Exit(1)
+		}
+		if err = webhooksamplev1.SetupMyChildResourceWebhookWithManager(mgr, webhookOpts); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MyChildResource")
+			os.Exit(1)
+		}
+		if err = webhooksamplev1.SetupChildFieldProtectionWebhookWithManager(mgr, webhookOpts); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ChildFieldProtection")
+			os.Exit(1)
+		}
+		if err = webhooksamplev1.SetupChildDeletionProtectionWebhookWithManager(mgr, webhookOpts); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ChildDeletionProtection")
+			os.Exit(1)
+		}
+		if err = webhooksamplev2.SetupMyResourceWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MyResource")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if cfg != nil {
+		watcher := config.NewWatcher(configFile, cfg, func(previous, current *config.Config) {
+			reloadConfig(previous, current, logLevel, resync, set)
+		})
+		if err := mgr.Add(watcher); err != nil {
+			setupLog.Error(err, "unable to set up the configuration file watcher")
+			os.Exit(1)
+		}
+	}
+
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(metricsCertWatcher); err != nil {
@@ -231,14 +456,203 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check")
+	if err := mgr.AddHealthzCheck("reconcile-watchdog", watchdog.Check); err != nil {
+		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	readyChecks := map[string]healthz.Checker{
+		"readyz":     healthz.Ping,
+		"cache-sync": controller.CacheSyncCheck(mgr.GetCache()),
+		"leader": controller.LeaderCheck(mgr.Elected(), mgr.GetAPIReader(), types.NamespacedName{
+			Namespace: leaderElectionNamespace(),
+			Name:      leaderElectionID,
+		}),
+	}
+	if serveWebhooks {
+		readyChecks["webhook"] = webhookServer.StartedChecker()
+	}
+	for name, check := range readyChecks {
+		if err := mgr.AddReadyzCheck(name, check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", name)
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		setupLog.Error(err, "unable to flush traces")
+	}
+	cancel()
+
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// leaderElectionNamespace returns the namespace of the leader lease, the
+// namespace of the pod as the manager defaults it to.
+func leaderElectionNamespace() string {
+	namespace, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(namespace))
+}
+
+// setupShard adds the shard of this replica, named after its host name, to
+// mgr. Shard leases are read and written directly rather than through the
+// cache of the manager, which may not watch their namespace.
+func setupShard(mgr ctrl.Manager) (*sharding.Shard, error) {
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	namespace := leaderElectionNamespace()
+	if namespace == "" {
+		return nil, errors.New("sharding requires running in a pod")
+	}
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return nil, err
+	}
+	shard := sharding.NewShard(c, sharding.Options{
+		Namespace: namespace,
+		Ring:      "myresource",
+		Identity:  identity,
+	})
+	return shard, mgr.Add(shard)
+}
+
+// controllerUsername returns the user the manager authenticates as, which
+// the child field and deletion protections admit.
+func controllerUsername(ctx context.Context, mgr ctrl.Manager) (string, error) {
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return "", err
+	}
+	review := &authenticationv1.SelfSubjectReview{}
+	if err := c.Create(ctx, review); err != nil {
+		return "", err
+	}
+	return review.Status.UserInfo.Username, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setFlags returns the names of the flags set on the command line.
+func setFlags() map[string]bool {
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	return set
+}
+
+// configFlags returns the values of cfg as flag values by flag name.
+func configFlags(cfg *config.Config) map[string]string {
+	values := map[string]string{
+		"metrics-bind-address":      cfg.Metrics.BindAddress,
+		"health-probe-bind-address": cfg.Health.ProbeBindAddress,
+		"zap-encoder":               cfg.Logging.Format,
+		"watch-namespaces":          strings.Join(cfg.Cache.Namespaces, ","),
+		"watch-label-selector":      cfg.Cache.LabelSelector,
+		"default-strategy":          string(cfg.Controller.DefaultStrategy),
+	}
+	if cfg.Metrics.Secure != nil {
+		values["metrics-secure"] = strconv.FormatBool(*cfg.Metrics.Secure)
+	}
+	if cfg.LeaderElection.Enabled != nil {
+		values["leader-elect"] = strconv.FormatBool(*cfg.LeaderElection.Enabled)
+	}
+	if cfg.Sharding.Enabled != nil {
+		values["sharding"] = strconv.FormatBool(*cfg.Sharding.Enabled)
+	}
+	ctrl := cfg.Controller
+	if ctrl.MaxConcurrentReconciles > 0 {
+		values["max-concurrent-reconciles"] = strconv.Itoa(ctrl.MaxConcurrentReconciles)
+	}
+	if ctrl.BackoffBase != nil {
+		values["reconcile-backoff-base"] = ctrl.BackoffBase.Duration.String()
+	}
+	if ctrl.BackoffMax != nil {
+		values["reconcile-backoff-max"] = ctrl.BackoffMax.Duration.String()
+	}
+	if ctrl.QPS > 0 {
+		values["reconcile-qps"] = strconv.FormatFloat(ctrl.QPS, 'f', -1, 64)
+	}
+	if ctrl.Burst > 0 {
+		values["reconcile-burst"] = strconv.Itoa(ctrl.Burst)
+	}
+	if ctrl.ResyncInterval != nil {
+		values["resync-interval"] = ctrl.ResyncInterval.Duration.String()
+	}
+	if cfg.Health.ReconcileStallTimeout != nil {
+		values["reconcile-stall-timeout"] = cfg.Health.ReconcileStallTimeout.Duration.String()
+	}
+	var gates []string
+	for _, name := range slices.Sorted(maps.Keys(cfg.FeatureGates)) {
+		gates = append(gates, name+"="+strconv.FormatBool(cfg.FeatureGates[name]))
+	}
+	values["feature-gates"] = strings.Join(gates, ",")
+	return values
+}
+
+// applyConfig sets the flags from the values of cfg unless they were set on
+// the command line, and the log level unless --zap-log-level was set.
+func applyConfig(cfg *config.Config, logLevel uberzap.AtomicLevel, set map[string]bool) error {
+	for name, value := range configFlags(cfg) {
+		if value == "" || set[name] {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return err
+		}
+	}
+	if cfg.Logging.Level != "" && !set["zap-log-level"] {
+		level, err := config.ParseLevel(cfg.Logging.Level)
+		if err != nil {
+			return err
+		}
+		logLevel.SetLevel(level)
+	}
+	return nil
+}
+
+// reloadConfig applies the settings of a changed configuration file that do
+// not require a restart: the log level and the resync interval.
+func reloadConfig(previous, current *config.Config, logLevel uberzap.AtomicLevel, resync *controller.Resync,
+	set map[string]bool) {
+	if !set["zap-log-level"] {
+		level := zapcore.InfoLevel
+		if current.Logging.Level != "" {
+			// The level was validated when loading the file.
+			level, _ = config.ParseLevel(current.Logging.Level)
+		}
+		logLevel.SetLevel(level)
+	}
+	if !set["resync-interval"] {
+		interval := controller.DefaultResyncInterval
+		if current.Controller.ResyncInterval != nil {
+			interval = current.Controller.ResyncInterval.Duration
+		}
+		resync.SetInterval(interval)
+	}
+	setupLog.Info("Reloaded the configuration file",
+		"logLevel", logLevel.Level().String(), "resyncInterval", resync.Interval().String())
+	if current.RestartRequired(previous) {
+		setupLog.Info("The configuration file changed settings that only apply after a restart")
+	}
+}
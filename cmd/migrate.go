@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"k8s-controller.ad/internal/migration"
+)
+
+// migrateStorage runs the migrate-storage subcommand: it rewrites every
+// MyResource and MyChildResource in the storage version of its CRD, then
+// records the storage version as the only stored version of the CRD. It
+// resumes from the progress recorded by an interrupted run.
+func migrateStorage(args []string) int {
+	var opts migration.Options
+	flags := flag.NewFlagSet("migrate-storage", flag.ExitOnError)
+	flags.StringVar(&opts.Namespace, "progress-namespace", leaderElectionNamespace(),
+		"The namespace of the ConfigMap recording the progress. Defaults to the namespace of the pod.")
+	flags.StringVar(&opts.Name, "progress-name", "k8s-controller-simple-storage-migration",
+		"The name of the ConfigMap recording the progress.")
+	flags.Int64Var(&opts.PageSize, "page-size", migration.DefaultPageSize,
+		"The number of objects listed and rewritten between two progress updates.")
+	if kubeconfig := flag.CommandLine.Lookup("kubeconfig"); kubeconfig != nil {
+		flags.Var(kubeconfig.Value, kubeconfig.Name, kubeconfig.Usage)
+	}
+	zapOpts := zap.Options{}
+	zapOpts.BindFlags(flags)
+	_ = flags.Parse(args)
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&zapOpts)))
+	logger := ctrl.Log.WithName("migrate-storage")
+
+	if opts.Namespace == "" {
+		logger.Error(nil, "--progress-namespace is required outside of a pod")
+		return 1
+	}
+	migrationScheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(migrationScheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(migrationScheme))
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		logger.Error(err, "unable to get the kubeconfig")
+		return 1
+	}
+	c, err := client.New(cfg, client.Options{Scheme: migrationScheme})
+	if err != nil {
+		logger.Error(err, "unable to create the client")
+		return 1
+	}
+
+	ctx := ctrl.LoggerInto(ctrl.SetupSignalHandler(), logger)
+	if _, err := migration.NewMigrator(c, opts).Run(ctx, migration.CRDs...); err != nil {
+		logger.Error(err, "storage version migration failed, run it again to resume")
+		return 1
+	}
+	return 0
+}
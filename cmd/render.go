@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+	"k8s-controller.ad/internal/features"
+	"k8s-controller.ad/internal/render"
+)
+
+// Exit codes of the render subcommand.
+const (
+	// renderFailed reports MyResources that failed validation or rendering.
+	renderFailed = 1
+	// renderInvalidInput reports invalid flags or unreadable files.
+	renderInvalidInput = 2
+)
+
+// renderChildren runs the render subcommand: it prints the children of the
+// MyResources in the files named by args, or stdin for "-", as the controller
+// would apply them. It needs no cluster.
+func renderChildren(args []string) int {
+	var output, namespace, childNamespaceAllowList, defaultStrategy string
+	featureGate := features.NewFeatureGate()
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: manager render [flags] FILE...")
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&output, "output", render.FormatYAML, "The output format, yaml or json.")
+	flags.StringVar(&output, "o", render.FormatYAML, "Shorthand for --output.")
+	flags.StringVar(&namespace, "namespace", "default", "The namespace of MyResources that set none.")
+	flags.StringVar(&childNamespaceAllowList, "child-namespace-allowlist", "",
+		"The namespaces children may be created in besides the namespace of their parent, as given to the manager.")
+	flags.StringVar(&defaultStrategy, "default-strategy", string(samplev1.ApplyStrategySuggested),
+		"The apply strategy of children whose parent and template set none, as given to the manager.")
+	flags.Var(features.Flag(featureGate), "feature-gates", "The feature gates of the manager.")
+	if err := flags.Parse(args); err != nil {
+		return renderInvalidInput
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return renderInvalidInput
+	}
+	if output != render.FormatYAML && output != render.FormatJSON {
+		fmt.Fprintf(os.Stderr, "error: unsupported output format %q, expected yaml or json\n", output)
+		return renderInvalidInput
+	}
+	if !slices.Contains(samplev1.ApplyStrategies, samplev1.ApplyStrategy(defaultStrategy)) {
+		fmt.Fprintf(os.Stderr, "error: unsupported default strategy %q\n", defaultStrategy)
+		return renderInvalidInput
+	}
+	namespaceAllowList, err := controller.ParseNamespaceAllowList(childNamespaceAllowList)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return renderInvalidInput
+	}
+	opts := render.Options{Namespace: namespace, DefaultStrategy: samplev1.ApplyStrategy(defaultStrategy)}
+	opts.Webhook.NamespaceAllowList = namespaceAllowList
+	opts.Webhook.Features = featureGate
+
+	type source struct {
+		name    string
+		parents []*samplev1.MyResource
+	}
+	sources := make([]source, 0, flags.NArg())
+	for _, name := range flags.Args() {
+		parents, err := decodeFile(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s: %v\n", name, err)
+			return renderInvalidInput
+		}
+		sources = append(sources, source{name: name, parents: parents})
+	}
+
+	code := 0
+	var children []*samplev1.MyChildResource
+	for _, src := range sources {
+		for _, parent := range src.parents {
+			rendered, err := render.Render(context.Background(), parent, opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %s: MyResource %s/%s: %v\n", src.name, parent.Namespace, parent.Name, err)
+				code = renderFailed
+				continue
+			}
+			children = append(children, rendered...)
+		}
+	}
+	if err := render.Write(os.Stdout, children, output); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return renderFailed
+	}
+	return code
+}
+
+// decodeFile reads the MyResources of the file name, stdin for "-".
+func decodeFile(name string) ([]*samplev1.MyResource, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close() //nolint:errcheck
+		r = f
+	}
+	return render.Decode(r)
+}
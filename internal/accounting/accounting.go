@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package accounting counts the API requests made through a client.Client,
+// per reconcile and in total, attributed to the apply strategy they were made
+// for.
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// NoStrategy labels requests made outside of a child apply.
+const NoStrategy = "none"
+
+// Sources of the accounted requests.
+const (
+	// SourceAPI labels requests sent to the API server.
+	SourceAPI = "api"
+	// SourceCache labels reads served from the cache of a client, which cost
+	// the API server nothing.
+	SourceCache = "cache"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "myresource_api_requests_total",
+		Help: "Number of API requests made by the controller by apply strategy, verb, resource and source. " +
+			"Reads served from a cache have the source cache and cost the API server nothing.",
+	}, []string{"strategy", "verb", "resource", "source"})
+
+	requestBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "myresource_api_request_bytes_total",
+		Help: "Number of request body bytes sent by the controller by apply strategy, verb and resource.",
+	}, []string{"strategy", "verb", "resource"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, requestBytesTotal)
+}
+
+// Key identifies a group of accounted requests. Source is SourceAPI or
+// SourceCache.
+type Key struct {
+	Strategy string
+	Verb     string
+	Resource string
+	Source   string
+}
+
+// String formats k as strategy/verb/resource, followed by /cache for reads
+// served from a cache.
+func (k Key) String() string {
+	if k.Source == SourceCache {
+		return fmt.Sprintf("%s/%s/%s/%s", k.Strategy, k.Verb, k.Resource, k.Source)
+	}
+	return fmt.Sprintf("%s/%s/%s", k.Strategy, k.Verb, k.Resource)
+}
+
+// Usage is the number of requests and bytes sent of a Key.
+type Usage struct {
+	Requests int
+	Bytes    int
+}
+
+// Requests accumulates the requests of one reconcile.
+type Requests struct {
+	mu    sync.Mutex
+	usage map[Key]Usage
+}
+
+// Usage returns a copy of the usage accumulated so far.
+func (r *Requests) Usage() map[Key]Usage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return maps.Clone(r.usage)
+}
+
+// Total returns the number of requests sent to the API server and bytes
+// accumulated so far. Reads served from a cache are left out.
+func (r *Requests) Total() Usage {
+	var total Usage
+	for key, usage := range r.Usage() {
+		if key.Source == SourceCache {
+			continue
+		}
+		total.Requests += usage.Requests
+		total.Bytes += usage.Bytes
+	}
+	return total
+}
+
+// LogValues returns the accumulated usage as logger key-value pairs.
+func (r *Requests) LogValues() []interface{} {
+	usage := r.Usage()
+	total := r.Total()
+	byKey := make(map[string]int, len(usage))
+	for key, u := range usage {
+		byKey[key.String()] = u.Requests
+	}
+	values := []interface{}{"requests", total.Requests, "bytes", total.Bytes}
+	for _, key := range slices.Sorted(maps.Keys(byKey)) {
+		values = append(values, key, byKey[key])
+	}
+	return values
+}
+
+func (r *Requests) add(key Key, bytes int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.usage == nil {
+		r.usage = map[Key]Usage{}
+	}
+	usage := r.usage[key]
+	usage.Requests++
+	usage.Bytes += bytes
+	r.usage[key] = usage
+}
+
+type requestsKey struct{}
+
+type strategyKey struct{}
+
+// WithRequests returns a context accumulating the requests made with it.
+func WithRequests(ctx context.Context) (context.Context, *Requests) {
+	requests := &Requests{}
+	return context.WithValue(ctx, requestsKey{}, requests), requests
+}
+
+// WithStrategy returns a context attributing the requests made with it to strategy.
+func WithStrategy(ctx context.Context, strategy string) context.Context {
+	return context.WithValue(ctx, strategyKey{}, strategy)
+}
+
+// record accounts a request made with ctx.
+func record(ctx context.Context, verb, resource, source string, bytes int) {
+	strategy, _ := ctx.Value(strategyKey{}).(string)
+	if strategy == "" {
+		strategy = NoStrategy
+	}
+	key := Key{Strategy: strategy, Verb: verb, Resource: resource, Source: source}
+
+	requestsTotal.WithLabelValues(key.Strategy, key.Verb, key.Resource, key.Source).Inc()
+	requestBytesTotal.WithLabelValues(key.Strategy, key.Verb, key.Resource).Add(float64(bytes))
+	if requests, ok := ctx.Value(requestsKey{}).(*Requests); ok {
+		requests.add(key, bytes)
+	}
+}
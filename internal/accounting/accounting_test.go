@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounting
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Accounting", func() {
+	It("should count the requests of a reconcile by strategy, verb and resource", func() {
+		c := NewClient(fake.NewClientBuilder().Build())
+		ctx, requests := WithRequests(context.Background())
+		applyCtx := WithStrategy(ctx, "Update")
+		patches := testutil.ToFloat64(requestsTotal.WithLabelValues("Update", "patch", "configmaps", SourceAPI))
+
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "accounted"}}
+		Expect(c.Create(applyCtx, cm)).To(Succeed())
+		base := cm.DeepCopy()
+		cm.Data = map[string]string{"key": "value"}
+		Expect(c.Patch(applyCtx, cm, client.MergeFrom(base))).To(Succeed())
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "accounted"}, cm)).To(Succeed())
+		Expect(c.List(ctx, &corev1.ConfigMapList{})).To(Succeed())
+
+		usage := requests.Usage()
+		Expect(usage).To(HaveLen(4))
+		Expect(usage[Key{Strategy: "Update", Verb: "create", Resource: "configmaps", Source: SourceAPI}].Bytes).
+			To(BeNumerically(">", 0))
+		Expect(usage[Key{Strategy: "Update", Verb: "patch", Resource: "configmaps", Source: SourceAPI}]).
+			To(Equal(Usage{Requests: 1, Bytes: len(`{"data":{"key":"value"}}`)}))
+		Expect(usage[Key{Strategy: NoStrategy, Verb: "get", Resource: "configmaps", Source: SourceAPI}]).
+			To(Equal(Usage{Requests: 1}))
+		Expect(usage[Key{Strategy: NoStrategy, Verb: "list", Resource: "configmaps", Source: SourceAPI}]).
+			To(Equal(Usage{Requests: 1}))
+		Expect(requests.Total().Requests).To(Equal(4))
+		Expect(requests.LogValues()).To(ContainElements("requests", 4, "Update/patch/configmaps", 1))
+
+		Expect(testutil.ToFloat64(requestsTotal.WithLabelValues("Update", "patch", "configmaps", SourceAPI))).
+			To(Equal(patches + 1))
+	})
+
+	It("should leave the reads served from a cache out of the total", func() {
+		c := NewCachedClient(fake.NewClientBuilder().Build())
+		ctx, requests := WithRequests(context.Background())
+
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cached"}}
+		Expect(c.Create(ctx, cm)).To(Succeed())
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), live)).To(Succeed())
+
+		usage := requests.Usage()
+		Expect(usage[Key{Strategy: NoStrategy, Verb: "get", Resource: "configmaps", Source: SourceCache}]).
+			To(Equal(Usage{Requests: 1}))
+		Expect(usage[Key{Strategy: NoStrategy, Verb: "get", Resource: "configmaps", Source: SourceAPI}]).
+			To(Equal(Usage{Requests: 1}))
+		Expect(requests.Total().Requests).To(Equal(2))
+		Expect(requests.LogValues()).To(ContainElements("requests", 2, "none/get/configmaps/cache", 1))
+	})
+
+	It("should only update the metrics outside of a reconcile", func() {
+		c := NewClient(fake.NewClientBuilder().Build())
+		deletes := testutil.ToFloat64(requestsTotal.WithLabelValues(NoStrategy, "delete", "configmaps", SourceAPI))
+
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unaccounted"}}
+		Expect(c.Create(context.Background(), cm)).To(Succeed())
+		Expect(c.Delete(context.Background(), cm)).To(Succeed())
+
+		Expect(testutil.ToFloat64(requestsTotal.WithLabelValues(NoStrategy, "delete", "configmaps", SourceAPI))).
+			To(Equal(deletes + 1))
+	})
+})
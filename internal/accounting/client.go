@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NewClient returns c accounting every request by verb, resource and bytes
+// sent in the request body.
+func NewClient(c client.Client) client.Client {
+	return &accountingClient{Client: c}
+}
+
+// NewCachedClient is NewClient for a client reading typed objects from a
+// cache, like the client of a manager or cluster. Those reads are accounted
+// with SourceCache; unstructured objects are read from the API server.
+func NewCachedClient(c client.Client) client.Client {
+	return &accountingClient{Client: c, cached: true}
+}
+
+type accountingClient struct {
+	client.Client
+	cached bool
+}
+
+func (c *accountingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	record(ctx, "get", c.resource(obj), c.readSource(obj), 0)
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *accountingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	record(ctx, "list", c.resource(list), c.readSource(list), 0)
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *accountingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	record(ctx, "create", c.resource(obj), SourceAPI, bodySize(obj))
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *accountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	record(ctx, "update", c.resource(obj), SourceAPI, bodySize(obj))
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *accountingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	record(ctx, "patch", c.resource(obj), SourceAPI, patchSize(obj, patch))
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *accountingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	record(ctx, "delete", c.resource(obj), SourceAPI, 0)
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *accountingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	record(ctx, "deletecollection", c.resource(obj), SourceAPI, 0)
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *accountingClient) Status() client.SubResourceWriter {
+	return &accountingStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+// accountingStatusWriter accounts the status updates of accountingClient.
+type accountingStatusWriter struct {
+	client.SubResourceWriter
+	client *accountingClient
+}
+
+func (w *accountingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	record(ctx, "update", w.client.resource(obj)+"/status", SourceAPI, bodySize(obj))
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+func (w *accountingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	record(ctx, "patch", w.client.resource(obj)+"/status", SourceAPI, patchSize(obj, patch))
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// readSource returns where a read of obj is served from.
+func (c *accountingClient) readSource(obj runtime.Object) string {
+	if _, unstructured := obj.(runtime.Unstructured); unstructured || !c.cached {
+		return SourceAPI
+	}
+	return SourceCache
+}
+
+// resource returns the plural resource name of obj, guessed from its kind when
+// it is not known to the REST mapper.
+func (c *accountingClient) resource(obj runtime.Object) string {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return "unknown"
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	mapping, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		resource, _ := meta.UnsafeGuessKindToResource(gvk)
+		return resource.Resource
+	}
+	return mapping.Resource.Resource
+}
+
+// bodySize returns the size of obj encoded as a request body.
+func bodySize(obj client.Object) int {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// patchSize returns the size of the body of patch applied to obj.
+func patchSize(obj client.Object, patch client.Patch) int {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
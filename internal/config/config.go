@@ -0,0 +1,250 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config contains the configuration file of the manager.
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/features"
+)
+
+const (
+	// APIVersion is the version of the configuration file format.
+	APIVersion = "config.k8s-controller.ad/v1alpha1"
+	// Kind is the kind of the configuration file.
+	Kind = "ManagerConfig"
+)
+
+// Log formats of LoggingConfig.
+const (
+	LogFormatJSON    = "json"
+	LogFormatConsole = "console"
+)
+
+// Config is the content of the manager configuration file. Every value left
+// empty falls back to the corresponding command line flag.
+type Config struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Metrics configures the metrics server.
+	Metrics MetricsConfig `json:"metrics,omitempty"`
+	// Health configures the health probe server.
+	Health HealthConfig `json:"health,omitempty"`
+	// LeaderElection configures the leader election of the manager.
+	LeaderElection LeaderElectionConfig `json:"leaderElection,omitempty"`
+	// Sharding configures the spreading of parents across replicas.
+	Sharding ShardingConfig `json:"sharding,omitempty"`
+	// Logging configures the logger. The level is reloaded without a restart.
+	Logging LoggingConfig `json:"logging,omitempty"`
+	// Cache configures the objects watched by the manager.
+	Cache CacheConfig `json:"cache,omitempty"`
+	// Controller configures the MyResource controller.
+	Controller ControllerConfig `json:"controller,omitempty"`
+	// FeatureGates enables or disables features by name.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// MetricsConfig configures the metrics server.
+type MetricsConfig struct {
+	// BindAddress is the address the metrics endpoint binds to, 0 to disable it.
+	BindAddress string `json:"bindAddress,omitempty"`
+	// Secure serves the metrics endpoint via HTTPS with authn/authz.
+	Secure *bool `json:"secure,omitempty"`
+}
+
+// HealthConfig configures the health probe server.
+type HealthConfig struct {
+	// ProbeBindAddress is the address the probe endpoint binds to.
+	ProbeBindAddress string `json:"probeBindAddress,omitempty"`
+	// ReconcileStallTimeout is the time after which a reconcile still running,
+	// or queued requests no reconcile completed for, fail the liveness check,
+	// 0 to disable the check.
+	ReconcileStallTimeout *metav1.Duration `json:"reconcileStallTimeout,omitempty"`
+}
+
+// LeaderElectionConfig configures the leader election of the manager.
+type LeaderElectionConfig struct {
+	// Enabled ensures there is only one active controller manager.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// ShardingConfig configures the spreading of parents across replicas.
+type ShardingConfig struct {
+	// Enabled spreads parents across all replicas by consistent hashing. It
+	// excludes leader election.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// LoggingConfig configures the logger.
+type LoggingConfig struct {
+	// Format is the encoding of log lines, json or console.
+	Format string `json:"format,omitempty"`
+	// Level is debug, info, error or an integer verbosity. It is reloaded
+	// without a restart.
+	Level string `json:"level,omitempty"`
+}
+
+// CacheConfig configures the objects watched by the manager.
+type CacheConfig struct {
+	// Namespaces are the namespaces watched by the manager, all of them when
+	// empty. They must include the namespaces children are allowed in.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// LabelSelector selects the MyResources watched by the manager.
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// ControllerConfig configures the workers, the rate limiter and the defaults
+// of the MyResource controller.
+type ControllerConfig struct {
+	// MaxConcurrentReconciles is the number of parents reconciled in parallel.
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+	// BackoffBase is the first per-item delay after a failed reconcile.
+	BackoffBase *metav1.Duration `json:"backoffBase,omitempty"`
+	// BackoffMax caps the per-item delay after repeated failures.
+	BackoffMax *metav1.Duration `json:"backoffMax,omitempty"`
+	// QPS is the overall rate of requeues allowed by the bucket limiter.
+	QPS float64 `json:"qps,omitempty"`
+	// Burst is the bucket size of the bucket limiter.
+	Burst int `json:"burst,omitempty"`
+	// DefaultStrategy applies the children of parents and templates setting
+	// no strategy.
+	DefaultStrategy samplev1.ApplyStrategy `json:"defaultStrategy,omitempty"`
+	// ResyncInterval is the delay after which a reconciled parent is
+	// reconciled again. It is reloaded without a restart.
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+}
+
+// Load reads and validates the configuration file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("validating %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the version and the values of the configuration.
+func (c *Config) Validate() error {
+	if c.APIVersion != APIVersion || c.Kind != Kind {
+		return fmt.Errorf("apiVersion and kind must be %s %s, not %q %q", APIVersion, Kind, c.APIVersion, c.Kind)
+	}
+
+	if ptr.Deref(c.Sharding.Enabled, false) && ptr.Deref(c.LeaderElection.Enabled, false) {
+		return fmt.Errorf("sharding.enabled and leaderElection.enabled are mutually exclusive")
+	}
+
+	switch c.Logging.Format {
+	case "", LogFormatJSON, LogFormatConsole:
+	default:
+		return fmt.Errorf("logging.format must be %s or %s", LogFormatJSON, LogFormatConsole)
+	}
+	if c.Logging.Level != "" {
+		if _, err := ParseLevel(c.Logging.Level); err != nil {
+			return fmt.Errorf("logging.level: %w", err)
+		}
+	}
+
+	if c.Health.ReconcileStallTimeout != nil && c.Health.ReconcileStallTimeout.Duration < 0 {
+		return fmt.Errorf("health.reconcileStallTimeout must not be negative")
+	}
+
+	if c.Cache.LabelSelector != "" {
+		if _, err := labels.Parse(c.Cache.LabelSelector); err != nil {
+			return fmt.Errorf("cache.labelSelector: %w", err)
+		}
+	}
+
+	ctrl := c.Controller
+	if ctrl.MaxConcurrentReconciles < 0 {
+		return fmt.Errorf("controller.maxConcurrentReconciles must not be negative")
+	}
+	if ctrl.BackoffBase != nil && ctrl.BackoffBase.Duration <= 0 {
+		return fmt.Errorf("controller.backoffBase must be positive")
+	}
+	if ctrl.BackoffMax != nil && ctrl.BackoffMax.Duration <= 0 {
+		return fmt.Errorf("controller.backoffMax must be positive")
+	}
+	if ctrl.BackoffBase != nil && ctrl.BackoffMax != nil && ctrl.BackoffBase.Duration > ctrl.BackoffMax.Duration {
+		return fmt.Errorf("controller.backoffBase must not exceed controller.backoffMax")
+	}
+	if ctrl.QPS < 0 {
+		return fmt.Errorf("controller.qps must not be negative")
+	}
+	if ctrl.Burst < 0 {
+		return fmt.Errorf("controller.burst must not be negative")
+	}
+	if ctrl.DefaultStrategy != "" && !slices.Contains(samplev1.ApplyStrategies, ctrl.DefaultStrategy) {
+		return fmt.Errorf("controller.defaultStrategy must be one of %v", samplev1.ApplyStrategies)
+	}
+	if ctrl.ResyncInterval != nil && ctrl.ResyncInterval.Duration <= 0 {
+		return fmt.Errorf("controller.resyncInterval must be positive")
+	}
+	if err := features.NewFeatureGate().SetFromMap(c.FeatureGates); err != nil {
+		return fmt.Errorf("featureGates: %w", err)
+	}
+	return nil
+}
+
+// ParseLevel parses a log level: debug, info, error or an integer verbosity,
+// where verbosity n is the zap level -n.
+func ParseLevel(level string) (zapcore.Level, error) {
+	switch level {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	verbosity, err := strconv.Atoi(level)
+	if err != nil || verbosity < 0 {
+		return 0, fmt.Errorf("%q is not debug, info, error or a non-negative integer", level)
+	}
+	return zapcore.Level(-verbosity), nil
+}
+
+// RestartRequired reports whether the change from previous to c touches
+// settings other than the ones reloaded without a restart: the log level and
+// the resync interval.
+func (c *Config) RestartRequired(previous *Config) bool {
+	current, old := *c, *previous
+	for _, cfg := range []*Config{&current, &old} {
+		cfg.Logging.Level = ""
+		cfg.Controller.ResyncInterval = nil
+	}
+	return !equality.Semantic.DeepEqual(current, old)
+}
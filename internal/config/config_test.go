@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+const header = "apiVersion: config.k8s-controller.ad/v1alpha1\nkind: ManagerConfig\n"
+
+var _ = Describe("Load", func() {
+	writeConfig := func(content string) string {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte(header+content), 0o600)).To(Succeed())
+		return path
+	}
+
+	It("should read the controller section", func() {
+		cfg, err := Load(writeConfig(`
+controller:
+  maxConcurrentReconciles: 4
+  backoffBase: 10ms
+  backoffMax: 5m
+  qps: 20.5
+  burst: 200
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Controller.MaxConcurrentReconciles).To(Equal(4))
+		Expect(cfg.Controller.BackoffBase.Duration).To(Equal(10 * time.Millisecond))
+		Expect(cfg.Controller.BackoffMax.Duration).To(Equal(5 * time.Minute))
+		Expect(cfg.Controller.QPS).To(Equal(20.5))
+		Expect(cfg.Controller.Burst).To(Equal(200))
+	})
+
+	It("should read the manager, logging and cache sections", func() {
+		cfg, err := Load(writeConfig(`
+metrics:
+  bindAddress: ":8443"
+  secure: false
+health:
+  probeBindAddress: ":9091"
+leaderElection:
+  enabled: true
+logging:
+  format: console
+  level: "2"
+cache:
+  namespaces: [team-a, team-b]
+  labelSelector: tier=gold
+controller:
+  defaultStrategy: SSA
+  resyncInterval: 1m
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Metrics.BindAddress).To(Equal(":8443"))
+		Expect(*cfg.Metrics.Secure).To(BeFalse())
+		Expect(cfg.Health.ProbeBindAddress).To(Equal(":9091"))
+		Expect(*cfg.LeaderElection.Enabled).To(BeTrue())
+		Expect(cfg.Logging.Format).To(Equal(LogFormatConsole))
+		Expect(cfg.Cache.Namespaces).To(Equal([]string{"team-a", "team-b"}))
+		Expect(cfg.Controller.DefaultStrategy).To(Equal(samplev1.ApplyStrategySSA))
+		Expect(cfg.Controller.ResyncInterval.Duration).To(Equal(time.Minute))
+		Expect(ParseLevel(cfg.Logging.Level)).To(Equal(zapcore.Level(-2)))
+	})
+
+	It("should reject a file without the current version", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte("controller:\n  burst: 2\n"), 0o600)).To(Succeed())
+		_, err := Load(path)
+		Expect(err).To(MatchError(ContainSubstring(APIVersion)))
+	})
+
+	DescribeTable("should reject invalid values",
+		func(content, field string) {
+			_, err := Load(writeConfig(content))
+			Expect(err).To(MatchError(ContainSubstring(field)))
+		},
+		Entry("stall timeout", "health:\n  reconcileStallTimeout: -1s\n", "health.reconcileStallTimeout"),
+		Entry("sharding with leader election", "sharding:\n  enabled: true\nleaderElection:\n  enabled: true\n",
+			"mutually exclusive"),
+		Entry("log format", "logging:\n  format: xml\n", "logging.format"),
+		Entry("log level", "logging:\n  level: verbose\n", "logging.level"),
+		Entry("label selector", "cache:\n  labelSelector: '!!'\n", "cache.labelSelector"),
+		Entry("default strategy", "controller:\n  defaultStrategy: Merge\n", "controller.defaultStrategy"),
+		Entry("resync interval", "controller:\n  resyncInterval: 0s\n", "controller.resyncInterval"),
+		Entry("feature gate", "featureGates:\n  Unknown: true\n", "featureGates"),
+	)
+
+	It("should reject unknown fields", func() {
+		_, err := Load(writeConfig("controller:\n  workers: 4\n"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject a backoff base above the backoff max", func() {
+		_, err := Load(writeConfig("controller:\n  backoffBase: 1m\n  backoffMax: 1s\n"))
+		Expect(err).To(MatchError(ContainSubstring("backoffBase")))
+	})
+})
+
+var _ = Describe("RestartRequired", func() {
+	It("should only ignore changes of the log level and the resync interval", func() {
+		previous := &Config{Logging: LoggingConfig{Level: "info"}}
+		current := &Config{
+			Logging:    LoggingConfig{Level: "debug"},
+			Controller: ControllerConfig{ResyncInterval: &metav1.Duration{Duration: time.Minute}},
+		}
+		Expect(current.RestartRequired(previous)).To(BeFalse())
+
+		current.Controller.DefaultStrategy = samplev1.ApplyStrategySSA
+		Expect(current.RestartRequired(previous)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Watcher", func() {
+	It("should hand valid changes of the file to the callback", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte(header+"logging:\n  level: info\n"), 0o600)).To(Succeed())
+		cfg, err := Load(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		changes := make(chan *Config, 10)
+		watcher := NewWatcher(path, cfg, func(previous, current *Config) {
+			Expect(previous.Logging.Level).To(Equal("info"))
+			changes <- current
+		})
+		watcher.pollInterval = 100 * time.Millisecond
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(watcher.Start(ctx)).To(Succeed())
+		}()
+
+		By("ignoring an invalid file")
+		Expect(os.WriteFile(path, []byte(header+"logging:\n  level: verbose\n"), 0o600)).To(Succeed())
+		Consistently(changes, 300*time.Millisecond).ShouldNot(Receive())
+
+		By("reloading a valid change")
+		Expect(os.WriteFile(path, []byte(header+"logging:\n  level: debug\n"), 0o600)).To(Succeed())
+		var current *Config
+		Eventually(changes).Should(Receive(&current))
+		Expect(current.Logging.Level).To(Equal("debug"))
+	})
+})
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultPollInterval is the interval at which Watcher re-reads the file in
+// case a change went unnoticed by the file system notifications.
+const DefaultPollInterval = 10 * time.Second
+
+// Watcher reloads the configuration file when it changes and hands every
+// valid and different configuration to a callback. Invalid files are logged
+// and ignored, keeping the last valid configuration.
+//
+// The directory of the file is watched rather than the file itself, so that
+// the symlink swaps of a mounted ConfigMap are noticed.
+type Watcher struct {
+	path         string
+	pollInterval time.Duration
+	current      *Config
+	onChange     func(previous, current *Config)
+}
+
+// NewWatcher returns a Watcher of the file at path, last loaded as current,
+// calling onChange with the previous and the reloaded configuration.
+func NewWatcher(path string, current *Config, onChange func(previous, current *Config)) *Watcher {
+	return &Watcher{
+		path:         path,
+		pollInterval: DefaultPollInterval,
+		current:      current,
+		onChange:     onChange,
+	}
+}
+
+// Start watches the file until ctx is done.
+func (w *Watcher) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close() //nolint:errcheck
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			w.reload(ctx)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.FromContext(ctx).Error(err, "Watching the configuration file failed", "path", w.path)
+		case <-ticker.C:
+			w.reload(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection lets every replica reload its configuration.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// reload loads the file and calls onChange if it changed.
+func (w *Watcher) reload(ctx context.Context) {
+	cfg, err := Load(w.path)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Ignoring the invalid configuration file", "path", w.path)
+		return
+	}
+	if equality.Semantic.DeepEqual(cfg, w.current) {
+		return
+	}
+	previous := w.current
+	w.current = cfg
+	w.onChange(previous, cfg)
+}
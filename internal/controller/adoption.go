@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+// AnnotationAdopted is set to "true" on children adopted by their parent
+// rather than created by it. Only adopted children are released when they stop
+// matching the adoption selector.
+const AnnotationAdopted = "sample.k8s-controller.ad/adopted"
+
+// errNotClaimable is wrapped by claimChild when a child exists but may not be
+// managed by the parent.
+var errNotClaimable = errors.New("child is not claimable")
+
+// adoptionSelector returns the selector of the MatchingSelector policy, or nil
+// when parent does not adopt by selector.
+func adoptionSelector(parent *samplev1.MyResource) (labels.Selector, error) {
+	if parent.Spec.Adoption == nil || parent.Spec.Adoption.Policy != samplev1.AdoptionPolicyMatchingSelector {
+		return nil, nil
+	}
+	if parent.Spec.Adoption.Selector == nil {
+		return labels.Nothing(), nil
+	}
+	return metav1.LabelSelectorAsSelector(parent.Spec.Adoption.Selector)
+}
+
+// canAdopt reports whether parent may adopt the live child without a controller.
+func canAdopt(parent *samplev1.MyResource, live *samplev1.MyChildResource) (bool, error) {
+	if parent.Spec.Adoption == nil {
+		return false, nil
+	}
+	switch parent.Spec.Adoption.Policy {
+	case samplev1.AdoptionPolicyAlways:
+		return true, nil
+	case samplev1.AdoptionPolicyMatchingSelector:
+		selector, err := adoptionSelector(parent)
+		if err != nil {
+			return false, err
+		}
+		return selector.Matches(labels.Set(live.Labels)), nil
+	default:
+		return false, nil
+	}
+}
+
+// claimChild makes sure the live object of desired, if any, is controlled by
+// parent. Children without a controller are adopted when the adoption policy
+// allows it: the tracking labels, the controller reference and the desired
+// fields are applied with ManagerName taking over field ownership. A child
+// controlled by another owner or refused by the policy results in an error
+// wrapping errNotClaimable. The live object read before claiming it is
+// returned, or nil when the child does not exist yet.
+//
+// AnnotationAdopted is set on desired for adopted children, so that every
+// strategy keeps it on the live object.
+func (r *MyResourceReconciler) claimChild(ctx context.Context, parent *samplev1.MyResource, desired *samplev1.MyChildResource) (*samplev1.MyChildResource, error) {
+	live := &samplev1.MyChildResource{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(desired), live); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	if uid, owner, ok := controllerOf(live); ok {
+		if uid == parent.UID {
+			if live.Annotations[AnnotationAdopted] == "true" {
+				desired.Annotations[AnnotationAdopted] = "true"
+			}
+			return live, nil
+		}
+		return nil, fmt.Errorf("%w: %s/%s is controlled by %s",
+			errNotClaimable, live.Namespace, live.Name, owner)
+	}
+
+	if !live.DeletionTimestamp.IsZero() {
+		return nil, fmt.Errorf("%w: %s/%s is being deleted", errNotClaimable, live.Namespace, live.Name)
+	}
+
+	adopt, err := canAdopt(parent, live)
+	if err != nil {
+		return nil, err
+	}
+	if !adopt {
+		return nil, fmt.Errorf("%w: %s/%s already exists and is not adopted by policy %q",
+			errNotClaimable, live.Namespace, live.Name, adoptionPolicy(parent))
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Adopting child resource", "namespace", live.Namespace, "name", live.Name)
+
+	desired.Annotations[AnnotationAdopted] = "true"
+	obj := desired.DeepCopy()
+	obj.SetGroupVersionKind(samplev1.GroupVersion.WithKind("MyChildResource"))
+	obj.ResourceVersion = live.ResourceVersion
+	unstr, err := toApplyUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	patchOpts := []client.PatchOption{
+		client.ForceOwnership,
+		client.FieldOwner(ManagerName),
+	}
+	if err := r.Client.Patch(ctx, &unstructured.Unstructured{Object: unstr}, client.Apply, patchOpts...); err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, errors.Join(err, fmt.Errorf("%s/%s changed while being adopted", live.Namespace, live.Name))
+		}
+		return nil, err
+	}
+	return live, nil
+}
+
+// releaseChildren removes the tracking labels and controller reference of
+// parent from adopted children that stopped matching the adoption selector.
+// Children created by parent are kept whatever their labels.
+func (r *MyResourceReconciler) releaseChildren(ctx context.Context, parent *samplev1.MyResource) error {
+	selector, err := adoptionSelector(parent)
+	if err != nil || selector == nil {
+		return err
+	}
+
+	// Adopted children carry the tracking labels, in whatever namespace.
+	list := &samplev1.MyChildResourceList{}
+	if err := r.Client.List(ctx, list, client.MatchingLabels{LabelParentUID: string(parent.UID)}); err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		child := &list.Items[i]
+		if !isControlledByParent(child, parent) || child.Annotations[AnnotationAdopted] != "true" ||
+			selector.Matches(labels.Set(child.Labels)) {
+			continue
+		}
+
+		ctrl.LoggerFrom(ctx).Info("Releasing child resource", "namespace", child.Namespace, "name", child.Name)
+
+		patch := client.MergeFromWithOptions(child.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		clearParentTracking(parent, child)
+		delete(child.Annotations, AnnotationAdopted)
+		if err := r.Client.Patch(ctx, child, patch); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func adoptionPolicy(parent *samplev1.MyResource) samplev1.AdoptionPolicy {
+	if parent.Spec.Adoption == nil || parent.Spec.Adoption.Policy == "" {
+		return samplev1.AdoptionPolicyNever
+	}
+	return parent.Spec.Adoption.Policy
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("Child adoption", func() {
+	const resourceName = "test-resource-adoption"
+	const childName = "test-resource-adoption-child"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	childKey := types.NamespacedName{
+		Name:      childName,
+		Namespace: "default",
+	}
+	var controllerReconciler *MyResourceReconciler
+
+	createParent := func(name string, adoption *samplev1.Adoption) *samplev1.MyResource {
+		resource := &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: samplev1.MyResourceSpec{
+				Strategy: samplev1.ApplyStrategySSA,
+				Adoption: adoption,
+				Children: []samplev1.ChildTemplate{{
+					Name:   childName,
+					Labels: map[string]string{"adopt": "yes"},
+					Spec:   samplev1.MyChildResourceSpec{Foo: "adopted"},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		return resource
+	}
+	getChild := func() *samplev1.MyChildResource {
+		child := &samplev1.MyChildResource{}
+		Expect(k8sClient.Get(ctx, childKey, child)).To(Succeed())
+		return child
+	}
+	reconcileParent := func() *metav1.Condition {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		resource := &samplev1.MyResource{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		return meta.FindStatusCondition(resource.Status.Conditions, samplev1.ConditionReady)
+	}
+
+	BeforeEach(func() {
+		controllerReconciler = &MyResourceReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+
+		By("creating a child that nobody owns")
+		child := getMyChildResource("default", childName)
+		child.Labels = map[string]string{"adopt": "yes"}
+		Expect(k8sClient.Create(ctx, child)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the parents, the child and the revisions")
+		Expect(k8sClient.DeleteAllOf(ctx, &samplev1.MyResource{}, client.InNamespace("default"))).To(Succeed())
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, getMyChildResource("default", childName)))).To(Succeed())
+		Expect(k8sClient.DeleteAllOf(ctx, &appsv1.ControllerRevision{}, client.InNamespace("default"))).To(Succeed())
+	})
+
+	It("should refuse an unowned child by default", func() {
+		createParent(resourceName, nil)
+
+		ready := reconcileParent()
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("ChildConflict"))
+		Expect(ready.Message).To(ContainSubstring(childName))
+		Expect(metav1.GetControllerOf(getChild())).To(BeNil())
+	})
+
+	It("should adopt an unowned child matching the selector", func() {
+		parent := createParent(resourceName, &samplev1.Adoption{
+			Policy: samplev1.AdoptionPolicyMatchingSelector,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"adopt": "yes"},
+			},
+		})
+
+		ready := reconcileParent()
+		Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+
+		child := getChild()
+		Expect(metav1.IsControlledBy(child, parent)).To(BeTrue())
+		Expect(child.Spec.Foo).To(Equal("adopted"))
+		Expect(child.Annotations).To(HaveKeyWithValue(AnnotationAdopted, "true"))
+		managers := make([]string, 0, len(child.ManagedFields))
+		for _, entry := range child.ManagedFields {
+			managers = append(managers, entry.Manager)
+		}
+		Expect(managers).To(ContainElement(ManagerName))
+
+		By("removing the selected label from the child")
+		child.Labels = map[string]string{"adopt": "no"}
+		Expect(k8sClient.Update(ctx, child)).To(Succeed())
+
+		ready = reconcileParent()
+		Expect(ready.Reason).To(Equal("ChildConflict"))
+		Expect(metav1.GetControllerOf(getChild())).To(BeNil())
+	})
+
+	It("should keep the children it created whatever their labels", func() {
+		const createdName = "test-resource-adoption-created"
+		parent := createParent(resourceName, &samplev1.Adoption{
+			Policy: samplev1.AdoptionPolicyMatchingSelector,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"adopt": "yes"},
+			},
+		})
+		parent.Spec.Children = append(parent.Spec.Children, samplev1.ChildTemplate{Name: createdName})
+		Expect(k8sClient.Update(ctx, parent)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, getMyChildResource("default", createdName)))).To(Succeed())
+		})
+
+		for range 2 {
+			ready := reconcileParent()
+			Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+		}
+		created := &samplev1.MyChildResource{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: createdName}, created)).To(Succeed())
+		Expect(metav1.IsControlledBy(created, parent)).To(BeTrue())
+		Expect(created.Annotations).NotTo(HaveKey(AnnotationAdopted))
+	})
+
+	It("should refuse a child controlled by another parent", func() {
+		other := createParent("test-resource-adoption-other", &samplev1.Adoption{
+			Policy: samplev1.AdoptionPolicyAlways,
+		})
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(other),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(metav1.IsControlledBy(getChild(), other)).To(BeTrue())
+
+		createParent(resourceName, &samplev1.Adoption{
+			Policy: samplev1.AdoptionPolicyAlways,
+		})
+		ready := reconcileParent()
+		Expect(ready.Reason).To(Equal("ChildConflict"))
+		Expect(ready.Message).To(ContainSubstring(other.Name))
+		Expect(metav1.IsControlledBy(getChild(), other)).To(BeTrue())
+	})
+})
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+// CacheScope restricts what the manager caches and therefore watches.
+type CacheScope struct {
+	// Namespaces are the namespaces cached, all namespaces when empty.
+	// Children created in other namespaces are neither watched nor readable
+	// through the cache, so every child namespace allowed by the
+	// NamespaceAllowList must be listed as well.
+	Namespaces []string
+	// LabelSelector selects the MyResources cached, all of them when nil.
+	// A parent that stops matching is no longer reconciled and keeps its
+	// children as they are.
+	LabelSelector labels.Selector
+}
+
+// CacheOptions returns the cache options of the manager for scope. The
+// managedFields of the cached children are dropped since the reconciler
+// never reads them and they make up a large part of every object.
+func CacheOptions(scope CacheScope) cache.Options {
+	opts := cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&samplev1.MyResource{}: {
+				Label: scope.LabelSelector,
+			},
+			&samplev1.MyChildResource{}: {
+				Transform: cache.TransformStripManagedFields(),
+			},
+		},
+	}
+	if len(scope.Namespaces) > 0 {
+		opts.DefaultNamespaces = make(map[string]cache.Config, len(scope.Namespaces))
+		for _, namespace := range scope.Namespaces {
+			opts.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+	return opts
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("Cache scope", func() {
+	It("should scope namespaces and parents and strip managedFields of children", func() {
+		selector := labels.SelectorFromSet(labels.Set{"tenant": "a"})
+		opts := CacheOptions(CacheScope{
+			Namespaces:    []string{"team-a", "shared"},
+			LabelSelector: selector,
+		})
+		Expect(opts.DefaultNamespaces).To(HaveKey("team-a"))
+		Expect(opts.DefaultNamespaces).To(HaveKey("shared"))
+
+		var parentConfig, childConfig cache.ByObject
+		for obj, config := range opts.ByObject {
+			switch obj.(type) {
+			case *samplev1.MyResource:
+				parentConfig = config
+			case *samplev1.MyChildResource:
+				childConfig = config
+			}
+		}
+		Expect(parentConfig.Label).To(Equal(selector))
+
+		child := getMyChildResource("default", "test-resource-cache-child")
+		child.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: ManagerName}}
+		transformed, err := childConfig.Transform(child)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transformed.(*samplev1.MyChildResource).ManagedFields).To(BeEmpty())
+	})
+
+	It("should cache every namespace by default", func() {
+		Expect(CacheOptions(CacheScope{}).DefaultNamespaces).To(BeEmpty())
+	})
+})
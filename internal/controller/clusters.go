@@ -0,0 +1,353 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/accounting"
+	"k8s-controller.ad/internal/features"
+	"k8s-controller.ad/internal/tracing"
+)
+
+const (
+	// AnnotationCluster records on a rendered child the cluster Secret of the
+	// remote cluster it is applied to.
+	AnnotationCluster = "sample.k8s-controller.ad/cluster"
+
+	// ClusterKubeconfigKey is the key of the kubeconfig in a cluster Secret.
+	ClusterKubeconfigKey = "kubeconfig"
+
+	// clusterSyncTimeout bounds the wait for the cache of a new remote cluster.
+	clusterSyncTimeout = 30 * time.Second
+)
+
+// ClusterPool keeps a client and a cache of MyChildResources per remote
+// cluster, keyed by the cluster Secret holding its kubeconfig. Clusters are
+// connected on first use and reconnected when their kubeconfig changes. A
+// cluster is connected outside of the lock of the pool, so that reconciles
+// using other clusters are not held back while its cache syncs. Events
+// of the remote children are sent to Events so that drift in a remote cluster
+// is reconciled like drift in the local one.
+type ClusterPool struct {
+	reader client.Reader
+	scheme *runtime.Scheme
+	events chan event.GenericEvent
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	clusters map[types.NamespacedName]*remoteCluster
+}
+
+type remoteCluster struct {
+	kubeconfig []byte
+	cancel     context.CancelFunc
+
+	// ready is closed once the cluster is connected, or failed to connect
+	// with err.
+	ready   chan struct{}
+	cluster cluster.Cluster
+	err     error
+}
+
+// NewClusterPool returns a pool reading cluster Secrets with reader, which
+// should not be cached to avoid watching every Secret of the cluster.
+func NewClusterPool(reader client.Reader, scheme *runtime.Scheme) *ClusterPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ClusterPool{
+		reader:   reader,
+		scheme:   scheme,
+		events:   make(chan event.GenericEvent, 1024),
+		ctx:      ctx,
+		cancel:   cancel,
+		clusters: map[types.NamespacedName]*remoteCluster{},
+	}
+}
+
+// Events returns the channel receiving the events of remote children.
+func (p *ClusterPool) Events() <-chan event.GenericEvent {
+	return p.events
+}
+
+// Start implements manager.Runnable and disconnects every cluster once ctx is done.
+func (p *ClusterPool) Start(ctx context.Context) error {
+	<-ctx.Done()
+	p.Stop()
+	return nil
+}
+
+// Stop disconnects every cluster of the pool.
+func (p *ClusterPool) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cancel()
+	for key, remote := range p.clusters {
+		remote.cancel()
+		delete(p.clusters, key)
+	}
+}
+
+// Client returns the traced client of the cluster whose kubeconfig is held by
+// the Secret named by key.
+func (p *ClusterPool) Client(ctx context.Context, key types.NamespacedName) (client.Client, error) {
+	secret := &corev1.Secret{}
+	if err := p.reader.Get(ctx, key, secret); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			p.disconnect(key)
+		}
+		return nil, err
+	}
+	kubeconfig := secret.Data[ClusterKubeconfigKey]
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("secret %s has no %s key", key, ClusterKubeconfigKey)
+	}
+
+	p.mu.Lock()
+	remote, ok := p.clusters[key]
+	if ok && !bytes.Equal(remote.kubeconfig, kubeconfig) {
+		ctrl.LoggerFrom(ctx).Info("Reconnecting remote cluster", "secret", key)
+		remote.cancel()
+		delete(p.clusters, key)
+		ok = false
+	}
+	if !ok {
+		clusterCtx, cancel := context.WithCancel(p.ctx)
+		remote = &remoteCluster{kubeconfig: kubeconfig, cancel: cancel, ready: make(chan struct{})}
+		p.clusters[key] = remote
+		go p.connect(clusterCtx, key, remote)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-remote.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if remote.err != nil {
+		return nil, errors.Join(remote.err, fmt.Errorf("failed to connect to the cluster of secret %s", key))
+	}
+	return accounting.NewCachedClient(tracing.NewClient(remote.cluster.GetClient())), nil
+}
+
+// connect starts the cluster of remote, running until ctx is done, and marks
+// it ready. A cluster failing to connect is removed from the pool, so that its
+// next use connects again.
+func (p *ClusterPool) connect(ctx context.Context, key types.NamespacedName, remote *remoteCluster) {
+	defer close(remote.ready)
+	remote.cluster, remote.err = p.startCluster(ctx, remote.kubeconfig)
+	if remote.err == nil {
+		return
+	}
+	remote.cancel()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.clusters[key] == remote {
+		delete(p.clusters, key)
+	}
+}
+
+// startCluster starts a cluster with a cache of MyChildResources forwarding
+// their events to the pool and waits for the cache to sync.
+func (p *ClusterPool) startCluster(ctx context.Context, kubeconfig []byte) (cluster.Cluster, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	config, err := restConfigFromKubeconfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	cl, err := cluster.New(config, func(o *cluster.Options) {
+		o.Scheme = p.scheme
+		o.Cache.ByObject = map[client.Object]cache.ByObject{
+			&samplev1.MyChildResource{}: {
+				Transform: cache.TransformStripManagedFields(),
+			},
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := cl.Start(ctx); err != nil {
+			ctrl.Log.WithName("clusters").Error(err, "remote cluster stopped", "host", config.Host)
+		}
+	}()
+
+	syncCtx, syncCancel := context.WithTimeout(ctx, clusterSyncTimeout)
+	defer syncCancel()
+	informer, err := cl.GetCache().GetInformer(syncCtx, &samplev1.MyChildResource{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    p.send,
+		UpdateFunc: func(_, obj interface{}) { p.send(obj) },
+		DeleteFunc: p.send,
+	}); err != nil {
+		return nil, err
+	}
+	if !cl.GetCache().WaitForCacheSync(syncCtx) {
+		return nil, errors.New("timed out waiting for the cache to sync")
+	}
+	return cl, nil
+}
+
+// restConfigFromKubeconfig returns the client config of a kubeconfig read
+// from a cluster Secret. Cluster Secrets are written by the users of the
+// controller, so their kubeconfig may only hold inline credentials: a token,
+// client certificate and key data, and certificate authority data. Exec and
+// auth provider plugins would run commands in the manager, and file paths
+// would read its files, such as the token of its service account.
+func restConfigFromKubeconfig(kubeconfig []byte) (*rest.Config, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(config.Clusters)) {
+		if config.Clusters[name].CertificateAuthority != "" {
+			errs = append(errs, fmt.Errorf("cluster %q: certificate-authority is not allowed", name))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.AuthInfos)) {
+		user := config.AuthInfos[name]
+		for _, field := range []struct {
+			name string
+			set  bool
+		}{
+			{"exec", user.Exec != nil},
+			{"auth-provider", user.AuthProvider != nil},
+			{"tokenFile", user.TokenFile != ""},
+			{"client-certificate", user.ClientCertificate != ""},
+			{"client-key", user.ClientKey != ""},
+			{"username", user.Username != ""},
+			{"password", user.Password != ""},
+			{"as", user.Impersonate != ""},
+			{"as-uid", user.ImpersonateUID != ""},
+			{"as-groups", len(user.ImpersonateGroups) > 0},
+			{"as-user-extra", len(user.ImpersonateUserExtra) > 0},
+		} {
+			if field.set {
+				errs = append(errs, fmt.Errorf("user %q: %s is not allowed", name, field.name))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, errors.Join(err, errors.New("the kubeconfig of a cluster Secret may only hold "+
+			"token, client-certificate-data, client-key-data and certificate-authority-data credentials"))
+	}
+	return clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
+
+// send forwards the event of a remote child to Events without blocking the informer.
+func (p *ClusterPool) send(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	child, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+	select {
+	case p.events <- event.GenericEvent{Object: child}:
+	default:
+		// The periodic resync of the parent catches up with dropped events.
+	}
+}
+
+// disconnect stops the cluster of the Secret named by key, if connected.
+func (p *ClusterPool) disconnect(key types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if remote, ok := p.clusters[key]; ok {
+		remote.cancel()
+		delete(p.clusters, key)
+	}
+}
+
+// clusterOf returns the cluster Secret name recorded on a rendered child.
+func clusterOf(child *samplev1.MyChildResource) string {
+	return child.Annotations[AnnotationCluster]
+}
+
+// forCluster returns a reconciler writing to the named remote cluster of
+// parent, or r itself for the local cluster. The returned reconciler shares
+// the apply strategies, adoption and pruning of r.
+func (r *MyResourceReconciler) forCluster(ctx context.Context, parent *samplev1.MyResource, name string) (*MyResourceReconciler, error) {
+	if name == "" {
+		return r, nil
+	}
+	if !features.Enabled(r.Features, features.RemoteClusters) {
+		return nil, fmt.Errorf("remote clusters require the %s feature gate", features.RemoteClusters)
+	}
+	if r.Clusters == nil {
+		return nil, errors.New("remote clusters are not enabled")
+	}
+	c, err := r.Clusters.Client(ctx, types.NamespacedName{Namespace: parent.Namespace, Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return &MyResourceReconciler{Client: c, Scheme: r.Scheme, Features: r.Features}, nil
+}
+
+// clusterStatuses returns the sorted statuses of the clusters synced by the
+// current reconcile. The last sync time is only moved when a cluster becomes
+// synced, so that periodic resyncs of a synced cluster leave the status as is.
+func clusterStatuses(previous []samplev1.ClusterStatus, current map[string]*samplev1.ClusterStatus) []samplev1.ClusterStatus {
+	if len(current) == 0 {
+		return nil
+	}
+	before := make(map[string]samplev1.ClusterStatus, len(previous))
+	for _, status := range previous {
+		before[status.Name] = status
+	}
+	now := metav1.Now()
+	statuses := make([]samplev1.ClusterStatus, 0, len(current))
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		status := *current[name]
+		status.LastSyncTime = before[name].LastSyncTime
+		if status.Synced && (!before[name].Synced || status.LastSyncTime == nil) {
+			status.LastSyncTime = &now
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("Remote clusters", Ordered, func() {
+	const resourceName = "test-resource-remote"
+	const childName = "test-resource-remote-child"
+	const secretName = "test-resource-remote-cluster"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	childKey := types.NamespacedName{
+		Name:      childName,
+		Namespace: "default",
+	}
+	var (
+		remoteEnv    *envtest.Environment
+		remoteClient client.Client
+		pool         *ClusterPool
+	)
+	var controllerReconciler *MyResourceReconciler
+
+	getParent := func() *samplev1.MyResource {
+		resource := &samplev1.MyResource{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		return resource
+	}
+
+	BeforeAll(func() {
+		By("starting a second API server as the remote cluster")
+		remoteEnv = &envtest.Environment{
+			CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+			ErrorIfCRDPathMissing: true,
+			BinaryAssetsDirectory: testEnv.BinaryAssetsDirectory,
+		}
+		remoteCfg, err := remoteEnv.Start()
+		Expect(err).NotTo(HaveOccurred())
+		remoteClient, err = client.New(remoteCfg, client.Options{Scheme: scheme.Scheme})
+		Expect(err).NotTo(HaveOccurred())
+
+		user, err := remoteEnv.AddUser(envtest.User{Name: "remote-admin", Groups: []string{"system:masters"}}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		kubeconfig, err := user.KubeConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "default"},
+			Data:       map[string][]byte{ClusterKubeconfigKey: kubeconfig},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+	})
+
+	AfterAll(func() {
+		Expect(k8sClient.Delete(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: "default"},
+		})).To(Succeed())
+		Expect(remoteEnv.Stop()).To(Succeed())
+	})
+
+	BeforeEach(func() {
+		pool = NewClusterPool(k8sClient, scheme.Scheme)
+		controllerReconciler = &MyResourceReconciler{
+			Client:   k8sClient,
+			Scheme:   k8sClient.Scheme(),
+			Clusters: pool,
+		}
+
+		By("creating a MyResource with a local and a remote child")
+		resource := &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: samplev1.MyResourceSpec{
+				Strategy: samplev1.ApplyStrategySSA,
+				Children: []samplev1.ChildTemplate{{
+					Name: childName,
+					Spec: samplev1.MyChildResourceSpec{Foo: "local"},
+				}, {
+					Name:    childName,
+					Cluster: secretName,
+					Spec:    samplev1.MyChildResourceSpec{Foo: "remote"},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		pool.Stop()
+
+		By("Cleanup the parent, its children and its revisions")
+		resource := &samplev1.MyResource{}
+		if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+			controllerutil.RemoveFinalizer(resource, FinalizerChildren)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+		}
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, getMyChildResource("default", childName)))).To(Succeed())
+		Expect(client.IgnoreNotFound(remoteClient.Delete(ctx, getMyChildResource("default", childName)))).To(Succeed())
+		Expect(k8sClient.DeleteAllOf(ctx, &appsv1.ControllerRevision{}, client.InNamespace("default"))).To(Succeed())
+	})
+
+	It("should apply children to their cluster and report its status", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		local := &samplev1.MyChildResource{}
+		Expect(k8sClient.Get(ctx, childKey, local)).To(Succeed())
+		Expect(local.Spec.Foo).To(Equal("local"))
+
+		remote := &samplev1.MyChildResource{}
+		Expect(remoteClient.Get(ctx, childKey, remote)).To(Succeed())
+		Expect(remote.Spec.Foo).To(Equal("remote"))
+		Expect(remote.OwnerReferences).To(BeEmpty())
+		Expect(remote.Labels).To(HaveKeyWithValue(LabelParentName, resourceName))
+
+		parent := getParent()
+		Expect(controllerutil.ContainsFinalizer(parent, FinalizerChildren)).To(BeTrue())
+		Expect(parent.Status.Inventory).To(ConsistOf(
+			samplev1.ChildReference{Namespace: "default", Name: childName},
+			samplev1.ChildReference{Cluster: secretName, Namespace: "default", Name: childName},
+		))
+		Expect(parent.Status.Clusters).To(HaveLen(1))
+		Expect(parent.Status.Clusters[0].Name).To(Equal(secretName))
+		Expect(parent.Status.Clusters[0].Synced).To(BeTrue())
+		Expect(parent.Status.Clusters[0].Children).To(Equal(int32(1)))
+
+		By("deleting the parent")
+		Expect(k8sClient.Delete(ctx, parent)).To(Succeed())
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(remoteClient.Get(ctx, childKey, &samplev1.MyChildResource{}))).To(BeTrue())
+	})
+
+	It("should report a cluster without a Secret and still apply local children", func() {
+		parent := getParent()
+		parent.Spec.Children[1].Cluster = "missing-cluster"
+		Expect(k8sClient.Update(ctx, parent)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, childKey, &samplev1.MyChildResource{})).To(Succeed())
+
+		parent = getParent()
+		Expect(parent.Status.Clusters).To(HaveLen(1))
+		Expect(parent.Status.Clusters[0].Synced).To(BeFalse())
+		Expect(parent.Status.Clusters[0].Message).NotTo(BeEmpty())
+		ready := meta.FindStatusCondition(parent.Status.Conditions, samplev1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Reason).To(Equal("ClusterSyncFailed"))
+	})
+})
+
+// stubCluster is a connected remote cluster serving c.
+type stubCluster struct {
+	cluster.Cluster
+	c client.Client
+}
+
+func (s stubCluster) GetClient() client.Client {
+	return s.c
+}
+
+var _ = Describe("Cluster pool", func() {
+	ctx := context.Background()
+
+	kubeconfigSecret := func(name, server string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Data:       map[string][]byte{ClusterKubeconfigKey: []byte("server: " + server)},
+		}
+	}
+
+	It("should serve connected clusters while another one connects", func() {
+		connecting, connected := kubeconfigSecret("connecting", "a"), kubeconfigSecret("connected", "b")
+		reader := fake.NewClientBuilder().WithObjects(connecting, connected).Build()
+		pool := NewClusterPool(reader, scheme.Scheme)
+		DeferCleanup(pool.Stop)
+
+		ready := make(chan struct{})
+		close(ready)
+		pool.clusters[client.ObjectKeyFromObject(connecting)] = &remoteCluster{
+			kubeconfig: connecting.Data[ClusterKubeconfigKey],
+			cancel:     func() {},
+			ready:      make(chan struct{}),
+		}
+		pool.clusters[client.ObjectKeyFromObject(connected)] = &remoteCluster{
+			kubeconfig: connected.Data[ClusterKubeconfigKey],
+			cancel:     func() {},
+			ready:      ready,
+			cluster:    stubCluster{c: fake.NewClientBuilder().Build()},
+		}
+
+		c, err := pool.Client(ctx, client.ObjectKeyFromObject(connected))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).NotTo(BeNil())
+
+		By("giving up on the connecting cluster with the context of the caller")
+		waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, err = pool.Client(waitCtx, client.ObjectKeyFromObject(connecting))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should only move the last sync time when a cluster becomes synced", func() {
+		synced := metav1.NewTime(time.Now().Add(-time.Hour))
+		previous := []samplev1.ClusterStatus{
+			{Name: "east", Synced: true, LastSyncTime: &synced},
+			{Name: "west", Synced: false, LastSyncTime: &synced},
+		}
+		statuses := clusterStatuses(previous, map[string]*samplev1.ClusterStatus{
+			"east":  {Name: "east", Synced: true},
+			"west":  {Name: "west", Synced: true},
+			"north": {Name: "north", Synced: false, Message: "unreachable"},
+		})
+		Expect(statuses).To(HaveLen(3))
+		Expect(statuses[0].Name).To(Equal("east"))
+		Expect(statuses[0].LastSyncTime).To(Equal(&synced))
+		Expect(statuses[1].Name).To(Equal("north"))
+		Expect(statuses[1].LastSyncTime).To(BeNil())
+		Expect(statuses[2].Name).To(Equal("west"))
+		Expect(statuses[2].LastSyncTime.After(synced.Time)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Cluster kubeconfigs", func() {
+	kubeconfig := func(mutate func(*clientcmdapi.Cluster, *clientcmdapi.AuthInfo)) []byte {
+		cluster := &clientcmdapi.Cluster{Server: "https://remote:6443", CertificateAuthorityData: []byte("ca")}
+		user := &clientcmdapi.AuthInfo{Token: "token"}
+		mutate(cluster, user)
+		config := clientcmdapi.NewConfig()
+		config.Clusters["remote"] = cluster
+		config.AuthInfos["user"] = user
+		config.Contexts["remote"] = &clientcmdapi.Context{Cluster: "remote", AuthInfo: "user"}
+		config.CurrentContext = "remote"
+		data, err := clientcmd.Write(*config)
+		Expect(err).NotTo(HaveOccurred())
+		return data
+	}
+
+	It("should accept inline credentials", func() {
+		config, err := restConfigFromKubeconfig(kubeconfig(func(_ *clientcmdapi.Cluster, user *clientcmdapi.AuthInfo) {
+			user.ClientCertificateData = []byte("cert")
+			user.ClientKeyData = []byte("key")
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Host).To(Equal("https://remote:6443"))
+		Expect(config.BearerToken).To(Equal("token"))
+		Expect(config.CertData).To(Equal([]byte("cert")))
+	})
+
+	DescribeTable("should reject credentials read from the manager or run by it",
+		func(field string, mutate func(*clientcmdapi.Cluster, *clientcmdapi.AuthInfo)) {
+			_, err := restConfigFromKubeconfig(kubeconfig(mutate))
+			Expect(err).To(MatchError(ContainSubstring(field + " is not allowed")))
+		},
+		Entry("exec plugins", "exec", func(_ *clientcmdapi.Cluster, user *clientcmdapi.AuthInfo) {
+			user.Exec = &clientcmdapi.ExecConfig{Command: "sh", APIVersion: "client.authentication.k8s.io/v1"}
+		}),
+		Entry("auth provider plugins", "auth-provider", func(_ *clientcmdapi.Cluster, user *clientcmdapi.AuthInfo) {
+			user.AuthProvider = &clientcmdapi.AuthProviderConfig{Name: "oidc"}
+		}),
+		Entry("token files", "tokenFile", func(_ *clientcmdapi.Cluster, user *clientcmdapi.AuthInfo) {
+			user.TokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+		}),
+		Entry("client certificate files", "client-certificate", func(_ *clientcmdapi.Cluster, user *clientcmdapi.AuthInfo) {
+			user.ClientCertificate = "/etc/tls/tls.crt"
+		}),
+		Entry("client key files", "client-key", func(_ *clientcmdapi.Cluster, user *clientcmdapi.AuthInfo) {
+			user.ClientKey = "/etc/tls/tls.key"
+		}),
+		Entry("certificate authority files", "certificate-authority", func(cluster *clientcmdapi.Cluster, _ *clientcmdapi.AuthInfo) {
+			cluster.CertificateAuthority = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+		}),
+		Entry("basic authentication", "password", func(_ *clientcmdapi.Cluster, user *clientcmdapi.AuthInfo) {
+			user.Username, user.Password = "admin", "secret"
+		}),
+		Entry("impersonation", "as", func(_ *clientcmdapi.Cluster, user *clientcmdapi.AuthInfo) {
+			user.Impersonate = "system:admin"
+		}),
+	)
+})
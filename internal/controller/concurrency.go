@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Defaults of ConcurrencyOptions, matching the controller-runtime defaults.
+const (
+	DefaultMaxConcurrentReconciles = 1
+	DefaultBackoffBase             = 5 * time.Millisecond
+	DefaultBackoffMax              = 1000 * time.Second
+	DefaultQPS                     = 10
+	DefaultBurst                   = 100
+)
+
+// ConcurrencyOptions configures the workers and the rate limiter of the
+// MyResource controller. Zero values fall back to the defaults above.
+type ConcurrencyOptions struct {
+	// MaxConcurrentReconciles is the number of parents reconciled in parallel.
+	MaxConcurrentReconciles int
+	// BackoffBase is the first per-item delay after a failed reconcile.
+	BackoffBase time.Duration
+	// BackoffMax caps the per-item delay after repeated failures.
+	BackoffMax time.Duration
+	// QPS is the overall rate of requeues allowed by the bucket limiter.
+	QPS float64
+	// Burst is the bucket size of the bucket limiter.
+	Burst int
+}
+
+// maxConcurrentReconciles returns the configured number of workers.
+func (o ConcurrencyOptions) maxConcurrentReconciles() int {
+	if o.MaxConcurrentReconciles <= 0 {
+		return DefaultMaxConcurrentReconciles
+	}
+	return o.MaxConcurrentReconciles
+}
+
+// rateLimiter returns the per-item exponential backoff combined with the
+// overall bucket limiter, like workqueue.DefaultTypedControllerRateLimiter.
+func (o ConcurrencyOptions) rateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	base, maxDelay := o.BackoffBase, o.BackoffMax
+	if base <= 0 {
+		base = DefaultBackoffBase
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultBackoffMax
+	}
+	qps, burst := o.QPS, o.Burst
+	if qps <= 0 {
+		qps = DefaultQPS
+	}
+	if burst <= 0 {
+		burst = DefaultBurst
+	}
+
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](base, maxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
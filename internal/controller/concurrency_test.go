@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+// The stress test is meant to be run with the race detector, see `make test-race`.
+var _ = Describe("Parallel reconciles", Label("stress"), func() {
+	const parents = 8
+	const rounds = 5
+
+	ctx := context.Background()
+
+	strategies := []samplev1.ApplyStrategy{
+		samplev1.ApplyStrategySSA,
+		samplev1.ApplyStrategyUpdate,
+		samplev1.ApplyStrategyReplace,
+		samplev1.ApplyStrategyPatch,
+		samplev1.ApplyStrategySuggested,
+	}
+	parentKey := func(i int) types.NamespacedName {
+		return types.NamespacedName{Name: fmt.Sprintf("test-resource-stress-%d", i), Namespace: "default"}
+	}
+
+	BeforeEach(func() {
+		By("creating parents with one child per strategy")
+		for i := range parents {
+			resource := &samplev1.MyResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      parentKey(i).Name,
+					Namespace: "default",
+				},
+			}
+			for _, strategy := range strategies {
+				resource.Spec.Children = append(resource.Spec.Children, samplev1.ChildTemplate{
+					Name:     fmt.Sprintf("%s-%s", parentKey(i).Name, strings.ToLower(string(strategy))),
+					Strategy: strategy,
+					Labels:   map[string]string{"parent": parentKey(i).Name},
+					Spec: samplev1.MyChildResourceSpec{
+						Foo:    parentKey(i).Name,
+						FooMap: map[string]string{"parent": parentKey(i).Name},
+					},
+				})
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		By("Cleanup the parents, their children and their revisions")
+		Expect(k8sClient.DeleteAllOf(ctx, &samplev1.MyResource{}, client.InNamespace("default"))).To(Succeed())
+		Expect(k8sClient.DeleteAllOf(ctx, &samplev1.MyChildResource{}, client.InNamespace("default"))).To(Succeed())
+		Expect(k8sClient.DeleteAllOf(ctx, &appsv1.ControllerRevision{}, client.InNamespace("default"))).To(Succeed())
+	})
+
+	It("should not leak state between parents reconciled in parallel", func() {
+		controllerReconciler := &MyResourceReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+
+		for range rounds {
+			var wg sync.WaitGroup
+			errs := make(chan error, parents)
+			for i := range parents {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: parentKey(i)})
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+
+		By("checking every child only carries the values of its own parent")
+		for i := range parents {
+			list := &samplev1.MyChildResourceList{}
+			Expect(k8sClient.List(ctx, list,
+				client.InNamespace("default"),
+				client.MatchingLabels{LabelParentName: parentKey(i).Name},
+			)).To(Succeed())
+			Expect(list.Items).To(HaveLen(len(strategies)))
+			for _, child := range list.Items {
+				Expect(child.Labels).To(HaveKeyWithValue("parent", parentKey(i).Name))
+				Expect(child.Spec.Foo).To(Equal(parentKey(i).Name))
+				Expect(child.Spec.FooMap).To(Equal(map[string]string{"parent": parentKey(i).Name}))
+			}
+		}
+	})
+})
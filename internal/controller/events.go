@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+// Reasons of the Events recorded on a MyResource for its children.
+const (
+	EventReasonChildCreated   = "ChildCreated"
+	EventReasonChildUpdated   = "ChildUpdated"
+	EventReasonChildUnchanged = "ChildUnchanged"
+	EventReasonChildConflict  = "ChildConflict"
+	EventReasonChildPruned    = "ChildPruned"
+	EventReasonChildFailed    = "ChildFailed"
+)
+
+// eventf records an Event on parent when the reconciler has a Recorder.
+func (r *MyResourceReconciler) eventf(parent *samplev1.MyResource, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(parent, eventType, reason, messageFmt, args...)
+}
+
+// childEvent records the outcome of applying child. Unchanged children are
+// only counted in unchanged, so that Reconcile records a single Event for them
+// the first time it observes a generation of the parent.
+func (r *MyResourceReconciler) childEvent(parent *samplev1.MyResource, child *samplev1.MyChildResource, result controllerutil.OperationResult, unchanged *int) {
+	switch result {
+	case controllerutil.OperationResultCreated:
+		r.eventf(parent, corev1.EventTypeNormal, EventReasonChildCreated, "Created child resource %s", childKey(child))
+	case controllerutil.OperationResultNone:
+		*unchanged++
+	default:
+		r.eventf(parent, corev1.EventTypeNormal, EventReasonChildUpdated, "Updated child resource %s", childKey(child))
+	}
+}
+
+// childKey names child in Events, with its remote cluster if any.
+func childKey(child *samplev1.MyChildResource) string {
+	return inventoryKey(childReference(child))
+}
+
+// inventoryKey names an inventory entry in Events.
+func inventoryKey(ref samplev1.ChildReference) string {
+	key := ref.Namespace + "/" + ref.Name
+	if ref.Cluster != "" {
+		key = ref.Cluster + ":" + key
+	}
+	return key
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("Child events", func() {
+	const resourceName = "test-resource-events"
+	const childName = "test-resource-events-child"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	var (
+		controllerReconciler *MyResourceReconciler
+		recorder             *record.FakeRecorder
+	)
+
+	reconcileParent := func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		controllerReconciler = &MyResourceReconciler{
+			Client:   k8sClient,
+			Scheme:   k8sClient.Scheme(),
+			Recorder: recorder,
+		}
+
+		resource := &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: samplev1.MyResourceSpec{
+				Strategy: samplev1.ApplyStrategySSA,
+				Children: []samplev1.ChildTemplate{{
+					Name: childName,
+					Spec: samplev1.MyChildResourceSpec{Foo: "events"},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the parent, its child and its revisions")
+		Expect(k8sClient.Delete(ctx, &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+		})).To(Succeed())
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, getMyChildResource("default", childName)))).To(Succeed())
+		Expect(k8sClient.DeleteAllOf(ctx, &appsv1.ControllerRevision{}, client.InNamespace("default"))).To(Succeed())
+	})
+
+	It("should record created, unchanged and pruned children", func() {
+		reconcileParent()
+		Expect(recorder.Events).To(Receive(Equal("Normal ChildCreated Created child resource default/" + childName)))
+
+		By("resyncing an observed generation")
+		reconcileParent()
+		Expect(recorder.Events).NotTo(Receive())
+
+		By("changing the parent without changing its children")
+		resource := &samplev1.MyResource{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		resource.Spec.RevisionHistoryLimit = ptr.To[int32](3)
+		Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		reconcileParent()
+		Expect(recorder.Events).To(Receive(Equal("Normal ChildUnchanged Skipped 1 unchanged child resources")))
+		reconcileParent()
+		Expect(recorder.Events).NotTo(Receive())
+
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		resource.Spec.Children = nil
+		Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+		reconcileParent()
+		Expect(recorder.Events).To(Receive(Equal("Normal ChildPruned Pruned child resource default/" + childName)))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+})
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/features"
+)
+
+var _ = Describe("Feature gates", func() {
+	var (
+		ctx        context.Context
+		calls      int
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		calls = 0
+		scheme := runtime.NewScheme()
+		utilruntime.Must(samplev1.AddToScheme(scheme))
+		fakeClient = interceptor.NewClient(fake.NewClientBuilder().WithScheme(scheme).Build(), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				calls++
+				return c.Get(ctx, key, obj, opts...)
+			},
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				calls++
+				return c.Create(ctx, obj, opts...)
+			},
+		})
+	})
+
+	childName := types.NamespacedName{Namespace: "default", Name: "test-resource-three-way-merge-child"}
+	threeWayMergeChild := func(labels map[string]string) *samplev1.MyChildResource {
+		child := getMyChildResource("default", "test-resource-three-way-merge-child")
+		child.Labels = labels
+		child.Annotations[AnnotationStrategy] = string(samplev1.ApplyStrategyThreeWayMerge)
+		return child
+	}
+
+	It("should leave the ThreeWayMerge strategy inert while its gate is disabled", func() {
+		r := &MyResourceReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Features: features.NewFeatureGate()}
+
+		_, err := r.applyChild(ctx, threeWayMergeChild(map[string]string{"a": "1"}))
+		Expect(err).To(MatchError(ContainSubstring("requires the ThreeWayMerge feature gate")))
+		Expect(calls).To(BeZero())
+	})
+
+	It("should refuse remote clusters while their gate is disabled", func() {
+		gate := features.NewFeatureGate()
+		Expect(gate.Set("RemoteClusters=false")).To(Succeed())
+		r := &MyResourceReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Features: gate, Clusters: &ClusterPool{}}
+
+		parent := &samplev1.MyResource{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-resource-gated"}}
+		_, err := r.forCluster(ctx, parent, "remote")
+		Expect(err).To(MatchError(ContainSubstring("require the RemoteClusters feature gate")))
+		Expect(calls).To(BeZero())
+	})
+
+	It("should remove fields dropped from the template and keep the fields of others", func() {
+		gate := features.NewFeatureGate()
+		Expect(gate.Set("ThreeWayMerge=true")).To(Succeed())
+		r := &MyResourceReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Features: gate}
+
+		result, err := r.applyChild(ctx, threeWayMergeChild(map[string]string{"a": "1", "b": "2"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(controllerutil.OperationResultCreated))
+
+		By("letting someone else add a label")
+		live := &samplev1.MyChildResource{}
+		Expect(fakeClient.Get(ctx, childName, live)).To(Succeed())
+		live.Labels["c"] = "3"
+		Expect(fakeClient.Update(ctx, live)).To(Succeed())
+
+		By("dropping a label from the template")
+		result, err = r.applyChild(ctx, threeWayMergeChild(map[string]string{"a": "1"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(controllerutil.OperationResultUpdated))
+		Expect(fakeClient.Get(ctx, childName, live)).To(Succeed())
+		Expect(live.Labels).To(HaveKeyWithValue("a", "1"))
+		Expect(live.Labels).NotTo(HaveKey("b"))
+		Expect(live.Labels).To(HaveKeyWithValue("c", "3"))
+
+		By("leaving an unchanged child alone")
+		result, err = r.applyChild(ctx, threeWayMergeChild(map[string]string{"a": "1"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(controllerutil.OperationResultNone))
+	})
+})
@@ -0,0 +1,174 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultStallTimeout is the time after which a reconcile still running, or
+// queued work no reconcile completed for, is considered stuck.
+const DefaultStallTimeout = 5 * time.Minute
+
+// cacheSyncTimeout bounds the wait for the informers within a probe.
+const cacheSyncTimeout = 500 * time.Millisecond
+
+// CacheSyncCheck returns a readiness check failing until the informers of c
+// have synced.
+func CacheSyncCheck(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("informer caches have not synced")
+		}
+		return nil
+	}
+}
+
+// LeaderCheck returns a readiness check failing while leadership is not
+// settled: the replica is ready once elected, which happens right away
+// without leader election, or while another replica holds an unexpired lease.
+// Standby replicas are ready, so that a rolling update can proceed.
+func LeaderCheck(elected <-chan struct{}, reader client.Reader, lease types.NamespacedName) healthz.Checker {
+	return func(req *http.Request) error {
+		select {
+		case <-elected:
+			return nil
+		default:
+		}
+
+		current := &coordinationv1.Lease{}
+		if err := reader.Get(req.Context(), lease, current); err != nil {
+			return fmt.Errorf("not elected and unable to get the leader lease: %w", err)
+		}
+		spec := current.Spec
+		if spec.HolderIdentity == nil || *spec.HolderIdentity == "" || spec.RenewTime == nil ||
+			spec.LeaseDurationSeconds == nil {
+			return errors.New("not elected and no replica holds the leader lease")
+		}
+		expiry := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+		if time.Now().After(expiry) {
+			return fmt.Errorf("not elected and the leader lease of %s expired at %s",
+				*spec.HolderIdentity, expiry.Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// Watchdog tracks the reconciles in flight and the work queue of the
+// controller. Its liveness check fails when a reconcile has run for longer
+// than its timeout, or when requests have been queued for longer than its
+// timeout without any reconcile completing, so that a stuck reconcile loop
+// gets the manager restarted.
+type Watchdog struct {
+	timeout time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	next    uint64
+	running map[uint64]time.Time
+	// queue is the work queue of the controller once it started.
+	queue interface{ Len() int }
+	// progress is the last time a reconcile completed or the queue was
+	// seen empty.
+	progress time.Time
+}
+
+// NewWatchdog returns a Watchdog failing after timeout. A zero timeout
+// disables the check.
+func NewWatchdog(timeout time.Duration) *Watchdog {
+	return &Watchdog{
+		timeout: timeout,
+		now:     time.Now,
+		running: map[uint64]time.Time{},
+	}
+}
+
+// begin records the start of a reconcile and returns the function recording
+// its end. It does nothing on a nil Watchdog.
+func (w *Watchdog) begin() func() {
+	if w == nil {
+		return func() {}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.next
+	w.next++
+	w.running[id] = w.now()
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.running, id)
+		w.progress = w.now()
+	}
+}
+
+// NewQueue creates the work queue of the controller like controller-runtime
+// does by default and watches it for progress. It is meant for the NewQueue
+// option of the controller.
+func (w *Watchdog) NewQueue(controllerName string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	queue := workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+		Name: controllerName,
+	})
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.queue = queue
+	w.progress = w.now()
+	return queue
+}
+
+// Check is the liveness check of the Watchdog.
+func (w *Watchdog) Check(_ *http.Request) error {
+	if w.timeout <= 0 {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.now()
+	for _, start := range w.running {
+		if elapsed := now.Sub(start); elapsed > w.timeout {
+			return fmt.Errorf("a reconcile has been running for %s, longer than %s", elapsed.Round(time.Second), w.timeout)
+		}
+	}
+	if w.queue == nil {
+		return nil
+	}
+	queued := w.queue.Len()
+	if queued == 0 {
+		w.progress = now
+		return nil
+	}
+	if stalled := now.Sub(w.progress); stalled > w.timeout {
+		return fmt.Errorf("%d requests are queued and no reconcile completed for %s, longer than %s",
+			queued, stalled.Round(time.Second), w.timeout)
+	}
+	return nil
+}
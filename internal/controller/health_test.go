@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Health checks", func() {
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	It("should not be ready before the caches sync", func() {
+		synced := false
+		informers := &informertest.FakeInformers{Synced: &synced}
+		Expect(CacheSyncCheck(informers)(req)).To(MatchError(ContainSubstring("not synced")))
+
+		synced = true
+		Expect(CacheSyncCheck(informers)(req)).To(Succeed())
+	})
+
+	It("should be ready when elected or while another replica leads", func() {
+		key := types.NamespacedName{Namespace: "default", Name: "leader"}
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To("other-replica"),
+				LeaseDurationSeconds: ptr.To[int32](15),
+				RenewTime:            &metav1.MicroTime{Time: time.Now()},
+			},
+		}
+		reader := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(lease).Build()
+		standby := make(chan struct{})
+		Expect(LeaderCheck(standby, reader, key)(req)).To(Succeed())
+
+		elected := make(chan struct{})
+		close(elected)
+		Expect(LeaderCheck(elected, reader, key)(req)).To(Succeed())
+
+		lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now().Add(-time.Minute)}
+		Expect(reader.Update(req.Context(), lease)).To(Succeed())
+		Expect(LeaderCheck(standby, reader, key)(req)).To(MatchError(ContainSubstring("expired")))
+
+		missing := types.NamespacedName{Namespace: "default", Name: "missing"}
+		Expect(LeaderCheck(standby, reader, missing)(req)).To(HaveOccurred())
+	})
+
+	It("should fail the liveness check when queued requests make no progress", func() {
+		now := time.Now()
+		watchdog := NewWatchdog(time.Minute)
+		watchdog.now = func() time.Time { return now }
+		queue := watchdog.NewQueue("test", workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer queue.ShutDown()
+
+		By("idling with an empty queue")
+		now = now.Add(2 * time.Minute)
+		Expect(watchdog.Check(req)).To(Succeed())
+
+		By("queueing a request no reconcile takes")
+		queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "stuck"}})
+		now = now.Add(30 * time.Second)
+		Expect(watchdog.Check(req)).To(Succeed())
+		now = now.Add(time.Minute)
+		Expect(watchdog.Check(req)).To(MatchError(ContainSubstring("1 requests are queued and no reconcile completed")))
+
+		By("completing a reconcile")
+		watchdog.begin()()
+		Expect(watchdog.Check(req)).To(Succeed())
+	})
+
+	It("should fail the liveness check when a reconcile runs for too long", func() {
+		now := time.Now()
+		watchdog := NewWatchdog(time.Minute)
+		watchdog.now = func() time.Time { return now }
+
+		done := watchdog.begin()
+		Expect(watchdog.Check(req)).To(Succeed())
+
+		now = now.Add(2 * time.Minute)
+		watchdog.begin()()
+		Expect(watchdog.Check(req)).To(MatchError(ContainSubstring("longer than 1m0s")))
+
+		done()
+		Expect(watchdog.Check(req)).To(Succeed())
+
+		var disabled *Watchdog
+		disabled.begin()()
+		Expect(NewWatchdog(0).Check(req)).To(Succeed())
+	})
+})
@@ -0,0 +1,203 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var (
+	// eventsTotal counts the watch events seen by the controller by kind,
+	// event type and whether the event predicates accepted or filtered them.
+	eventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "myresource_controller_events_total",
+		Help: "Number of watch events accepted or filtered by the event predicates.",
+	}, []string{"kind", "event", "result"})
+
+	// childApplyDuration observes every child apply by strategy and outcome.
+	childApplyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "myresource_child_apply_duration_seconds",
+		Help:    "Duration of child applies by apply strategy and result.",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"strategy", "result"})
+
+	// childDriftedFields observes how many fields of an existing child
+	// differed from the desired state before it was applied.
+	childDriftedFields = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "myresource_child_drifted_fields",
+		Help:    "Number of drifted fields corrected per applied child by apply strategy.",
+		Buckets: []float64{0, 1, 2, 5, 10, 20, 50},
+	}, []string{"strategy"})
+
+	// childApplyConflictsTotal counts child applies rejected with a conflict.
+	// Server-side applies force the ownership of their fields and never
+	// conflict; the conflicts are updates of children changed since they were
+	// read.
+	childApplyConflictsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "myresource_child_apply_conflicts_total",
+		Help: "Number of child applies rejected with a conflict, such as a child changed since it was read, by apply strategy.",
+	}, []string{"strategy"})
+
+	childrenDesc = prometheus.NewDesc("myresource_children",
+		"Number of MyChildResources by status.state.", []string{"state"}, nil)
+	parentsDesc = prometheus.NewDesc("myresource_parents",
+		"Number of MyResources by the status of their Ready condition.", []string{"ready"}, nil)
+)
+
+func init() {
+	metrics.Registry.MustRegister(eventsTotal, childApplyDuration, childDriftedFields, childApplyConflictsTotal)
+
+	// Series of every strategy exist from the start, so that rates and
+	// alerts do not depend on the first observation.
+	for _, strategy := range samplev1.ApplyStrategies {
+		childDriftedFields.WithLabelValues(string(strategy))
+		childApplyConflictsTotal.WithLabelValues(string(strategy))
+	}
+}
+
+// observeApply records the metrics of a child apply with strategy. live is the
+// child read before the apply, nil when it did not exist.
+func observeApply(strategy samplev1.ApplyStrategy, live, desired *samplev1.MyChildResource,
+	result controllerutil.OperationResult, err error, duration time.Duration) {
+	outcome := string(result)
+	if err != nil {
+		outcome = "error"
+		if apierrors.IsConflict(err) {
+			childApplyConflictsTotal.WithLabelValues(string(strategy)).Inc()
+		}
+	}
+	childApplyDuration.WithLabelValues(string(strategy), outcome).Observe(duration.Seconds())
+
+	if err == nil && live != nil {
+		childDriftedFields.WithLabelValues(string(strategy)).Observe(float64(driftedFields(live, desired)))
+	}
+}
+
+// driftedFields counts the labels, annotations and spec fields set in desired
+// whose value differs in live. Lists count as a single field.
+func driftedFields(live, desired *samplev1.MyChildResource) int {
+	l, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return 0
+	}
+	d, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, path := range [][]string{{"metadata", "labels"}, {"metadata", "annotations"}, {"spec"}} {
+		desiredValue, found, _ := unstructured.NestedFieldNoCopy(d, path...)
+		if !found {
+			continue
+		}
+		liveValue, _, _ := unstructured.NestedFieldNoCopy(l, path...)
+		count += countDrift(desiredValue, liveValue)
+	}
+	return count
+}
+
+func countDrift(desired, live interface{}) int {
+	if desiredMap, ok := desired.(map[string]interface{}); ok {
+		liveMap, _ := live.(map[string]interface{})
+		count := 0
+		for key, value := range desiredMap {
+			count += countDrift(value, liveMap[key])
+		}
+		return count
+	}
+	if equality.Semantic.DeepEqual(desired, live) {
+		return 0
+	}
+	return 1
+}
+
+// stateCollector reports the number of children and parents by state from
+// the cache of the manager at scrape time.
+type stateCollector struct {
+	reader client.Reader
+}
+
+// registerStateCollector registers a stateCollector reading from reader once.
+func registerStateCollector(reader client.Reader) error {
+	err := metrics.Registry.Register(stateCollector{reader: reader})
+	if errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+		return nil
+	}
+	return err
+}
+
+func (c stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- childrenDesc
+	ch <- parentsDesc
+}
+
+func (c stateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	log := ctrl.Log.WithName("metrics")
+
+	// Errors only drop the series, a failing scrape would hide every other metric.
+	children := &samplev1.MyChildResourceList{}
+	if err := c.reader.List(ctx, children); err != nil {
+		log.V(1).Info("Not collecting child states", "error", err.Error())
+	} else {
+		states := map[string]int{}
+		for _, child := range children.Items {
+			state := child.Status.State
+			if state == "" {
+				state = "Unknown"
+			}
+			states[state]++
+		}
+		for state, count := range states {
+			ch <- prometheus.MustNewConstMetric(childrenDesc, prometheus.GaugeValue, float64(count), state)
+		}
+	}
+
+	parents := &samplev1.MyResourceList{}
+	if err := c.reader.List(ctx, parents); err != nil {
+		log.V(1).Info("Not collecting parent states", "error", err.Error())
+		return
+	}
+	ready := map[string]int{}
+	for _, parent := range parents.Items {
+		status := "Unknown"
+		if condition := meta.FindStatusCondition(parent.Status.Conditions, samplev1.ConditionReady); condition != nil {
+			status = string(condition.Status)
+		}
+		ready[status]++
+	}
+	for status, count := range ready {
+		ch <- prometheus.MustNewConstMetric(parentsDesc, prometheus.GaugeValue, float64(count), status)
+	}
+}
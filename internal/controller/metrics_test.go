@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("Apply metrics", func() {
+	It("should count the drifted labels, annotations and spec fields", func() {
+		live := getMyChildResource("default", "test-resource-metrics-child")
+		live.Labels["team"] = "a"
+		live.Spec.Foo = "old"
+		live.Spec.FooMap = map[string]string{"kept": "yes", "changed": "old"}
+
+		desired := live.DeepCopy()
+		desired.Labels["team"] = "b"
+		desired.Annotations["new"] = "yes"
+		desired.Spec.Foo = "new"
+		desired.Spec.FooMap["changed"] = "new"
+		desired.Spec.FooList = []string{"a"}
+
+		Expect(driftedFields(live, desired)).To(Equal(5))
+		Expect(driftedFields(live, live.DeepCopy())).To(Equal(0))
+	})
+
+	It("should count the conflicts of children changed since they were read", func() {
+		ctx := context.Background()
+		scheme := runtime.NewScheme()
+		utilruntime.Must(samplev1.AddToScheme(scheme))
+		c := interceptor.NewClient(fake.NewClientBuilder().WithScheme(scheme).Build(), interceptor.Funcs{
+			Update: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.UpdateOption) error {
+				return apierrors.NewConflict(schema.GroupResource{Group: samplev1.GroupVersion.Group, Resource: "mychildresources"},
+					obj.GetName(), errors.New("the object has been modified"))
+			},
+		})
+		r := &MyResourceReconciler{Client: c, Scheme: scheme}
+		parent := &samplev1.MyResource{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-resource-metrics", UID: "parent-uid"}}
+		child := getMyChildResource("default", "test-resource-metrics-conflict")
+		child.Annotations[AnnotationStrategy] = string(samplev1.ApplyStrategyUpdate)
+		child.Spec.Foo = "desired"
+		conflicts := testutil.ToFloat64(childApplyConflictsTotal.WithLabelValues(string(samplev1.ApplyStrategyUpdate)))
+
+		_, err := r.syncChild(ctx, parent, child)
+		Expect(apierrors.IsConflict(err)).To(BeTrue(), "%v", err)
+		Expect(testutil.ToFloat64(childApplyConflictsTotal.WithLabelValues(string(samplev1.ApplyStrategyUpdate)))).
+			To(Equal(conflicts + 1))
+		Expect(testutil.CollectAndCount(childApplyConflictsTotal)).To(Equal(len(samplev1.ApplyStrategies)))
+	})
+})
@@ -20,320 +20,612 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/component-base/featuregate"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/accounting"
+	"k8s-controller.ad/internal/features"
+	"k8s-controller.ad/internal/sharding"
+	"k8s-controller.ad/internal/tracing"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
-var (
-	AnnotationOrigin = map[string]string{
-		"test-mode": "origin",
-	}
-	AnnotationModified = map[string]string{
-		"test-mode": "modified",
-	}
-
-	LabelsOrigin = map[string]string{
-		"test-mode": "origin",
-		"counter":   "0",
-		"imOrigin":  "yes",
-	}
-	LabelsModified = map[string]string{
-		"test-mode": "modified",
-		"counter":   "0",
-	}
-
-	SpecOrigin = samplev1.MyChildResourceSpec{
-		Foo: "foo",
-		FooMap: map[string]string{
-			"key1": "value1",
-			"key2": "value1-2",
-		},
-		FooList: []string{"1", "2", "3"},
-	}
-	SpecModified = samplev1.MyChildResourceSpec{
-		Foo: "foo-2",
-		FooMap: map[string]string{
-			"key1": "value1",
-		},
-		FooList: []string{"1", "2"},
-	}
-)
-
 const (
 	ManagerName   = "ssa-manager"
 	AnnotationKey = "manifest_applied"
+
+	// AnnotationStrategy records on a rendered child the strategy it is applied with.
+	AnnotationStrategy = "sample.k8s-controller.ad/apply-strategy"
+	// AnnotationLastApplied records on a child the last child applied with
+	// ApplyStrategyThreeWayMerge.
+	AnnotationLastApplied = "sample.k8s-controller.ad/last-applied"
 )
 
 // MyResourceReconciler reconciles a MyResource object
 type MyResourceReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// NamespaceAllowList restricts the namespaces children may be created in
+	// besides the namespace of their parent.
+	NamespaceAllowList NamespaceAllowList
+	// Concurrency configures the workers and the rate limiter of the controller.
+	// Reconcile keeps no state between calls and is safe to run in parallel.
+	Concurrency ConcurrencyOptions
+	// EventFilters configures which watch events trigger a reconcile.
+	EventFilters EventFilterOptions
+	// Recorder records Events on the parent for every child action. Events
+	// are not recorded when it is nil.
+	Recorder record.EventRecorder
+	// Clusters connects to the remote clusters of child templates setting a
+	// cluster. Such children are refused when it is nil.
+	Clusters *ClusterPool
+	// DefaultStrategy applies the children of parents and templates setting
+	// no strategy, ApplyStrategySuggested when empty.
+	DefaultStrategy samplev1.ApplyStrategy
+	// Resync sets the delay after which a reconciled parent is reconciled
+	// again, DefaultResyncInterval when nil.
+	Resync *Resync
+	// Features gates experimental behaviour. Features enabled by default are
+	// enabled when it is nil.
+	Features featuregate.FeatureGate
+	// Watchdog tracks the reconciles in flight and the work queue for the
+	// liveness check. It is optional.
+	Watchdog *Watchdog
+	// Shard restricts the reconciled parents to the ones assigned to this
+	// replica. Every parent is reconciled when it is nil.
+	Shard *sharding.Shard
 }
 
 // +kubebuilder:rbac:groups=sample.k8s-controller.ad,resources=myresources,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sample.k8s-controller.ad,resources=myresources/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sample.k8s-controller.ad,resources=myresources/finalizers,verbs=update
+// +kubebuilder:rbac:groups=sample.k8s-controller.ad,resources=mychildresources,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the MyResource object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
+//
+// The children of a MyResource are rendered from spec.children, recorded as a
+// revision and applied with their apply strategy. While spec.rollbackTo is set
+// the children recorded in that revision are applied instead. Children left
+// over from a previous child set are found in status.inventory and pruned.
+// Children may live in other namespaces than their parent when allowed by
+// NamespaceAllowList, or in remote clusters reached through Clusters; they are
+// tracked by labels instead of owner references. With a Shard, only the
+// parents assigned to this replica are reconciled, once it claimed them with
+// its shard label; the label is removed from the parents it no longer owns.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.0/pkg/reconcile
-func (r *MyResourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *MyResourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile MyResource", trace.WithAttributes(
+		tracing.ParentNamespaceKey.String(req.Namespace),
+		tracing.ParentNameKey.String(req.Name),
+	))
+	defer func() { tracing.End(span, err) }()
+	defer r.Watchdog.begin()()
+
 	log := ctrl.LoggerFrom(ctx)
 	log.Info("Reconciling MyResource", "namespace", req.Namespace, "name", req.Name)
 
-	// // Call the reconcileChildResourceSSA function
-	// if err := r.reconcileChildResourceSSA(ctx); err != nil {
-	// 	errors.Join(err, errors.New("failed to reconcile child resource by SSA"))
-	// 	return ctrl.Result{}, err
-	// }
-	// // Call the reconcileChildResourceWithUpdate function
-	// if err := r.reconcileChildResourceWithUpdateCurrent(ctx); err != nil {
-	// 	errors.Join(err, errors.New("failed to reconcile child resource by update"))
-	// 	return ctrl.Result{}, err
-	// }
-	// // Call the reconcileChildResourceWithUpdate function
-	// if err := r.reconcileChildResourceWithReplace(ctx); err != nil {
-	// 	errors.Join(err, errors.New("failed to reconcile child resource by update"))
-	// 	return ctrl.Result{}, err
-	// }
-	// // Call the reconcileChildResourceWithPatch function
-	// if err := r.reconcileChildResourceWithPatchCurrent(ctx); err != nil {
-	// 	errors.Join(err, errors.New("failed to reconcile child resource by patch"))
-	// 	return ctrl.Result{}, err
-	// }
-	// Call the reconcileChildResource function
-	if err := r.reconcileChildResourceSuggestion(ctx); err != nil {
-		errors.Join(err, errors.New("failed to reconcile child resource by patch"))
-		return ctrl.Result{}, err
-	}
-
-	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
-}
+	// Requests of parents moved to another replica hand them over.
+	if r.Shard != nil {
+		done, ok := r.Shard.Acquire(req.String())
+		if !ok {
+			log.V(1).Info("Releasing MyResource assigned to another shard")
+			return ctrl.Result{}, r.releaseShard(ctx, req.NamespacedName)
+		}
+		defer done()
+	}
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *MyResourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&samplev1.MyResource{}).
-		Named("myresource").
-		Complete(r)
-}
+	ctx, requests := accounting.WithRequests(ctx)
+	defer func() { log.V(1).Info("API requests of reconcile", requests.LogValues()...) }()
 
-// reconcileChildResourceSSA contains SSA logic for child resource
-func (r *MyResourceReconciler) reconcileChildResourceSSA(ctx context.Context) error {
-	name := "example-resource-ssa"
+	parent := &samplev1.MyResource{}
+	if err := r.Client.Get(ctx, req.NamespacedName, parent); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if claimed, err := r.claimShard(ctx, parent); err != nil {
+		return ctrl.Result{}, errors.Join(err, errors.New("failed to claim the shard"))
+	} else if !claimed {
+		return ctrl.Result{RequeueAfter: shardHandoffRetry}, nil
+	}
 
-	if err := CreateChildResource(ctx, r.Client, name); err != nil {
-		return err
+	if !parent.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalizeChildren(ctx, parent)
 	}
 
-	desired := getMyChildResource(name)
-	current := getMyChildResource(name)
-	if err := r.Client.Get(
-		ctx, client.ObjectKeyFromObject(current), current,
-	); err != nil {
-		return err
+	revisions, err := listRevisions(ctx, r.Client, parent)
+	if err != nil {
+		return ctrl.Result{}, errors.Join(err, errors.New("failed to list revisions"))
 	}
 
-	if current.Labels["skip-change"] != "yes" {
-		if current.Labels["test-mode"] == "origin" {
-			desired.SetLabels(LabelsModified)
-			desired.Spec = SpecModified
-			desired.Status.State = "done"
-		} else {
-			desired.SetLabels(LabelsOrigin)
-			desired.Spec = SpecOrigin
+	var (
+		children []*samplev1.MyChildResource
+		current  *appsv1.ControllerRevision
+		reason   = "Applied"
+	)
+	if parent.Spec.RollbackTo != nil {
+		current = findRevision(revisions, parent.Spec.RollbackTo.Revision)
+		if current == nil {
+			err := fmt.Errorf("revision %d not found", parent.Spec.RollbackTo.Revision)
+			return ctrl.Result{}, reconcile.TerminalError(r.setNotReady(ctx, parent, "RollbackRevisionNotFound", err))
+		}
+		if children, err = childrenFromRevision(current); err != nil {
+			return ctrl.Result{}, reconcile.TerminalError(r.setNotReady(ctx, parent, "RollbackRevisionInvalid", err))
+		}
+		reason = "RolledBack"
+	} else {
+		if children, err = RenderChildren(parent, r.DefaultStrategy); err != nil {
+			return ctrl.Result{}, reconcile.TerminalError(r.setNotReady(ctx, parent, "RenderFailed", err))
+		}
+		if current, err = r.syncRevision(ctx, parent, revisions, children); err != nil {
+			return ctrl.Result{}, errors.Join(err, errors.New("failed to record revision"))
 		}
-	}
-	patchOpts := []client.PatchOption{
-		client.ForceOwnership,
-		client.FieldOwner(ManagerName),
 	}
 
-	desired.SetGroupVersionKind(samplev1.GroupVersion.WithKind("MyChildResource"))
-	desired.ManagedFields = nil
-	return r.Client.Patch(ctx, desired, client.Apply, patchOpts...)
-}
+	for _, child := range children {
+		if !r.NamespaceAllowList.Allowed(parent.Namespace, child.Namespace) {
+			err := fmt.Errorf("child resource %s may not be created in namespace %s", child.Name, child.Namespace)
+			return ctrl.Result{}, reconcile.TerminalError(r.setNotReady(ctx, parent, "NamespaceNotAllowed", err))
+		}
+	}
 
-func (r *MyResourceReconciler) reconcileChildResourceWithUpdateCurrent(ctx context.Context) error {
-	name := "example-resource-update-current"
+	if needsChildrenFinalizer(parent, children) && !controllerutil.ContainsFinalizer(parent, FinalizerChildren) {
+		controllerutil.AddFinalizer(parent, FinalizerChildren)
+		if err := r.Client.Update(ctx, parent); err != nil {
+			return ctrl.Result{}, errors.Join(err, errors.New("failed to add finalizer"))
+		}
+	}
 
-	if err := CreateChildResource(ctx, r.Client, name); err != nil {
-		return err
+	if err := r.releaseChildren(ctx, parent); err != nil {
+		return ctrl.Result{}, errors.Join(err, errors.New("failed to release child resources"))
 	}
-	desired := getMyChildResource(name)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, desired, func() error {
-		target := desired.DeepCopy()
+	var conflicts, clusterErrs []error
+	inventory := make([]samplev1.ChildReference, 0, len(children))
+	clusters := map[string]*samplev1.ClusterStatus{}
+	unchanged := 0
+	for _, child := range children {
+		child = child.DeepCopy()
+		cluster := clusterOf(child)
+		if cluster != "" && clusters[cluster] == nil {
+			clusters[cluster] = &samplev1.ClusterStatus{Name: cluster, Synced: true}
+		}
+		if cluster != "" && !clusters[cluster].Synced {
+			// The remaining children of a failing cluster wait for the next resync.
+			inventory = append(inventory, childReference(child))
+			continue
+		}
 
-		if desired.Labels["skip-change"] != "yes" {
-			if desired.Labels["test-mode"] == "origin" {
-				target.SetLabels(LabelsModified)
-				target.Spec = SpecModified
-			} else {
-				target.SetLabels(LabelsOrigin)
-				target.Spec = SpecOrigin
-			}
+		result, err := r.syncChild(ctx, parent, child)
+		switch {
+		case errors.Is(err, errNotClaimable):
+			r.eventf(parent, corev1.EventTypeWarning, EventReasonChildConflict, "%v", err)
+			conflicts = append(conflicts, err)
+			continue
+		case err != nil:
+			r.eventf(parent, corev1.EventTypeWarning, EventReasonChildFailed,
+				"Failed to apply child resource %s: %v", childKey(child), err)
+		}
+		switch {
+		case err != nil && cluster != "":
+			// A failing remote cluster does not hold back the other clusters.
+			// The child is kept in the inventory since it may exist there.
+			clusters[cluster].Synced = false
+			clusters[cluster].Message = err.Error()
+			clusterErrs = append(clusterErrs, fmt.Errorf("cluster %s: %w", cluster, err))
+			inventory = append(inventory, childReference(child))
+			continue
+		case err != nil:
+			return ctrl.Result{}, r.setNotReady(ctx, parent, "ApplyFailed", err)
 		}
+		r.childEvent(parent, child, result, &unchanged)
+		inventory = append(inventory, childReference(child))
+		if cluster != "" {
+			clusters[cluster].Children++
+		}
+	}
+	switch {
+	case unchanged == 0:
+	case parent.Status.ObservedGeneration != parent.Generation:
+		r.eventf(parent, corev1.EventTypeNormal, EventReasonChildUnchanged,
+			"Skipped %d unchanged child resources", unchanged)
+	default:
+		// Periodic resyncs of an observed generation are only logged, so
+		// that they do not use up the Events the spam filter of the event
+		// recorder lets through for the parent.
+		log.V(1).Info("Skipped unchanged child resources", "count", unchanged)
+	}
 
-		d, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
-		if err != nil {
-			return err
+	if err := r.pruneChildren(ctx, parent, children); err != nil {
+		err = errors.Join(err, errors.New("failed to prune child resources"))
+		return ctrl.Result{}, r.setNotReady(ctx, parent, "PruneFailed", err)
+	}
+
+	if err := r.truncateHistory(ctx, parent, revisions, current); err != nil {
+		return ctrl.Result{}, errors.Join(err, errors.New("failed to clean up revision history"))
+	}
+
+	parent.Status.ObservedGeneration = parent.Generation
+	parent.Status.CurrentRevision = current.Name
+	parent.Status.Revision = current.Revision
+	parent.Status.Inventory = inventory
+	parent.Status.Clusters = clusterStatuses(parent.Status.Clusters, clusters)
+	ready := metav1.Condition{
+		Type:               samplev1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            fmt.Sprintf("%d children applied from revision %d", len(children), current.Revision),
+		ObservedGeneration: parent.Generation,
+	}
+	if len(conflicts) > 0 {
+		// Conflicts are resolved outside of this resource, so they are only
+		// reported and retried with the regular resync.
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "ChildConflict"
+		ready.Message = errors.Join(conflicts...).Error()
+	} else if len(clusterErrs) > 0 {
+		// Remote clusters are retried with the regular resync as well.
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "ClusterSyncFailed"
+		ready.Message = errors.Join(clusterErrs...).Error()
+	}
+	meta.SetStatusCondition(&parent.Status.Conditions, ready)
+	if err := r.Client.Status().Update(ctx, parent); err != nil {
+		return ctrl.Result{}, errors.Join(err, errors.New("failed to update status"))
+	}
+
+	return ctrl.Result{RequeueAfter: r.Resync.Interval()}, nil
+}
+
+// syncChild claims and applies a rendered child in the cluster it targets.
+func (r *MyResourceReconciler) syncChild(ctx context.Context, parent *samplev1.MyResource, child *samplev1.MyChildResource) (result controllerutil.OperationResult, err error) {
+	strategy := samplev1.ApplyStrategy(child.Annotations[AnnotationStrategy])
+	ctx, span := tracing.Tracer().Start(ctx, "Apply MyChildResource", trace.WithAttributes(
+		tracing.ParentNamespaceKey.String(parent.Namespace),
+		tracing.ParentNameKey.String(parent.Name),
+		tracing.ChildNamespaceKey.String(child.Namespace),
+		tracing.ChildNameKey.String(child.Name),
+		tracing.ChildClusterKey.String(clusterOf(child)),
+		tracing.StrategyKey.String(string(strategy)),
+	))
+	defer func() {
+		outcome := string(result)
+		if errors.Is(err, errNotClaimable) {
+			outcome = "conflict"
+		} else if err != nil {
+			outcome = "error"
 		}
+		span.SetAttributes(tracing.OutcomeKey.String(outcome))
+		tracing.End(span, err)
+	}()
 
-		t, err := runtime.DefaultUnstructuredConverter.ToUnstructured(target)
-		if err != nil {
-			return err
+	if err := setParentTracking(parent, child, r.Scheme); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	target, err := r.forCluster(ctx, parent, clusterOf(child))
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	live, err := target.claimChild(ctx, parent, child)
+	if err != nil {
+		if errors.Is(err, errNotClaimable) {
+			return controllerutil.OperationResultNone, err
 		}
+		return controllerutil.OperationResultNone, errors.Join(err, fmt.Errorf("failed to claim child resource %s", child.Name))
+	}
+	start := time.Now()
+	result, err = target.applyChild(accounting.WithStrategy(ctx, string(strategy)), child)
+	observeApply(strategy, live, child, result, err, time.Since(start))
+	if err != nil {
+		return controllerutil.OperationResultNone, errors.Join(err, fmt.Errorf("failed to apply child resource %s", child.Name))
+	}
+	return result, nil
+}
 
-		result := chartutil.CoalesceTables(t, d)
-		runtime.DefaultUnstructuredConverter.FromUnstructured(result, desired)
-		return nil
+// setNotReady records a failed Ready condition on the parent and returns cause
+// joined with any error hit while updating the status.
+func (r *MyResourceReconciler) setNotReady(ctx context.Context, parent *samplev1.MyResource, reason string, cause error) error {
+	parent.Status.ObservedGeneration = parent.Generation
+	meta.SetStatusCondition(&parent.Status.Conditions, metav1.Condition{
+		Type:               samplev1.ConditionReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            cause.Error(),
+		ObservedGeneration: parent.Generation,
 	})
-
-	return err
+	if err := r.Client.Status().Update(ctx, parent); err != nil {
+		return errors.Join(cause, err, errors.New("failed to update status"))
+	}
+	return cause
 }
 
-func (r *MyResourceReconciler) reconcileChildResourceWithReplace(ctx context.Context) error {
-	name := "example-resource-update-replace"
-
-	if err := CreateChildResource(ctx, r.Client, name); err != nil {
+// SetupWithManager sets up the controller with the Manager.
+func (r *MyResourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := registerStateCollector(mgr.GetCache()); err != nil {
 		return err
 	}
-	desired := getMyChildResource(name)
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, desired, func() error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&samplev1.MyResource{}, builder.WithPredicates(r.EventFilters.parentPredicate(), r.shardPredicate())).
+		Watches(&samplev1.MyChildResource{}, handler.EnqueueRequestsFromMapFunc(childToParent),
+			builder.WithPredicates(r.EventFilters.childPredicate()))
+	if r.Clusters != nil {
+		b = b.WatchesRawSource(source.Channel(r.Clusters.Events(), handler.EnqueueRequestsFromMapFunc(childToParent)))
+	}
+	if r.Shard != nil {
+		b = b.WatchesRawSource(r.shardSource(mgr.GetClient()))
+	}
+	opts := controller.Options{
+		MaxConcurrentReconciles: r.Concurrency.maxConcurrentReconciles(),
+		RateLimiter:             r.Concurrency.rateLimiter(),
+	}
+	if r.Watchdog != nil {
+		opts.NewQueue = r.Watchdog.NewQueue
+	}
+	return b.
+		WithOptions(opts).
+		Named("myresource").
+		Complete(r)
+}
 
-		if desired.Labels["skip-change"] != "yes" {
-			if desired.Labels["test-mode"] == "origin" {
-				desired.SetLabels(LabelsModified)
-				desired.Spec = SpecModified
-			} else {
-				desired.SetLabels(LabelsOrigin)
-				desired.Spec = SpecOrigin
-			}
+// applyChild writes a rendered child to the cluster with the strategy recorded
+// in its AnnotationStrategy annotation and reports whether the child was
+// created, updated or left unchanged.
+func (r *MyResourceReconciler) applyChild(ctx context.Context, desired *samplev1.MyChildResource) (controllerutil.OperationResult, error) {
+	switch strategy := samplev1.ApplyStrategy(desired.Annotations[AnnotationStrategy]); strategy {
+	case samplev1.ApplyStrategySSA:
+		return r.reconcileChildResourceSSA(ctx, desired)
+	case samplev1.ApplyStrategyUpdate:
+		return r.reconcileChildResourceWithUpdateCurrent(ctx, desired)
+	case samplev1.ApplyStrategyReplace:
+		return r.reconcileChildResourceWithReplace(ctx, desired)
+	case samplev1.ApplyStrategyPatch:
+		return r.reconcileChildResourceWithPatchCurrent(ctx, desired)
+	case samplev1.ApplyStrategySuggested:
+		return r.reconcileChildResourceSuggestion(ctx, desired)
+	case samplev1.ApplyStrategyThreeWayMerge:
+		if !features.Enabled(r.Features, features.ThreeWayMerge) {
+			return controllerutil.OperationResultNone,
+				fmt.Errorf("apply strategy %q requires the %s feature gate", strategy, features.ThreeWayMerge)
 		}
-		return nil
-	})
-
-	return err
+		return r.reconcileChildResourceThreeWayMerge(ctx, desired)
+	default:
+		return controllerutil.OperationResultNone, fmt.Errorf("unknown apply strategy %q", strategy)
+	}
 }
 
-func (r *MyResourceReconciler) reconcileChildResourceWithPatchCurrent(ctx context.Context) error {
-	name := "example-resource-patch-current"
+// reconcileChildResourceSSA contains SSA logic for child resource
+func (r *MyResourceReconciler) reconcileChildResourceSSA(ctx context.Context, desired *samplev1.MyChildResource) (controllerutil.OperationResult, error) {
+	current, created, err := createChildResource(ctx, r.Client, desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	patchOpts := []client.PatchOption{
+		client.ForceOwnership,
+		client.FieldOwner(ManagerName),
+	}
 
-	if err := CreateChildResource(ctx, r.Client, name); err != nil {
-		return err
+	obj := desired.DeepCopy()
+	obj.SetGroupVersionKind(samplev1.GroupVersion.WithKind("MyChildResource"))
+	obj.ManagedFields = nil
+	if err := r.Client.Patch(ctx, obj, client.Apply, patchOpts...); err != nil {
+		return controllerutil.OperationResultNone, err
 	}
-	desired := getMyChildResource(name)
+	return applyResult(created, current.ResourceVersion, obj.ResourceVersion), nil
+}
 
-	_, err := controllerutil.CreateOrPatch(ctx, r.Client, desired, func() error {
-		target := desired.DeepCopy()
+func (r *MyResourceReconciler) reconcileChildResourceWithUpdateCurrent(ctx context.Context, desired *samplev1.MyChildResource) (controllerutil.OperationResult, error) {
+	_, created, err := createChildResource(ctx, r.Client, desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	current := getMyChildResource(desired.Namespace, desired.Name)
 
-		if desired.Labels["skip-change"] != "yes" {
-			if desired.Labels["test-mode"] == "origin" {
-				target.SetLabels(LabelsModified)
-				target.Spec = SpecModified
-			} else {
-				target.SetLabels(LabelsOrigin)
-				target.Spec = SpecOrigin
-			}
-		}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, current, func() error {
+		return coalesceChildResource(current, desired)
+	})
 
-		d, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
-		if err != nil {
-			return err
-		}
+	return createdOr(created, result), err
+}
 
-		t, err := runtime.DefaultUnstructuredConverter.ToUnstructured(target)
-		if err != nil {
-			return err
-		}
+func (r *MyResourceReconciler) reconcileChildResourceWithReplace(ctx context.Context, desired *samplev1.MyChildResource) (controllerutil.OperationResult, error) {
+	_, created, err := createChildResource(ctx, r.Client, desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	current := getMyChildResource(desired.Namespace, desired.Name)
 
-		result := chartutil.CoalesceTables(t, d)
-		runtime.DefaultUnstructuredConverter.FromUnstructured(result, desired)
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, current, func() error {
+		current.SetLabels(desired.Labels)
+		current.SetAnnotations(desired.Annotations)
+		current.SetOwnerReferences(desired.OwnerReferences)
+		current.Spec = desired.Spec
 		return nil
 	})
 
-	return err
+	return createdOr(created, result), err
 }
 
-func (r *MyResourceReconciler) reconcileChildResourceSuggestion(ctx context.Context) error {
-	name := "example-resource-suggested"
+func (r *MyResourceReconciler) reconcileChildResourceWithPatchCurrent(ctx context.Context, desired *samplev1.MyChildResource) (controllerutil.OperationResult, error) {
+	_, created, err := createChildResource(ctx, r.Client, desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	current := getMyChildResource(desired.Namespace, desired.Name)
 
-	// request current
-	current := getMyChildResource(name)
-	if err := r.Client.Get(
-		ctx, client.ObjectKeyFromObject(current), current,
-	); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return err
-		}
+	result, err := controllerutil.CreateOrPatch(ctx, r.Client, current, func() error {
+		return coalesceChildResource(current, desired)
+	})
 
-		if err := CreateChildResource(ctx, r.Client, name); err != nil {
-			return err
-		}
-		return nil
+	return createdOr(created, result), err
+}
 
+func (r *MyResourceReconciler) reconcileChildResourceSuggestion(ctx context.Context, desired *samplev1.MyChildResource) (controllerutil.OperationResult, error) {
+	current, created, err := createChildResource(ctx, r.Client, desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
 	}
 
-	desired := getMyChildResource(name)
-	// if current.Labels["skip-change"] != "yes" {
-	// 	if current.Labels["test-mode"] == "origin" {
-	// 		desired.SetAnnotations(AnnotationModified)
-	// 		desired.SetLabels(LabelsModified)
-	// 		desired.Spec = SpecModified
-	// 	} else {
-	// 		desired.SetAnnotations(AnnotationOrigin)
-	// 		desired.SetLabels(LabelsOrigin)
-	// 		desired.Spec = SpecOrigin
-	// 	}
-	// }
-
 	patchOpts := []client.PatchOption{
 		client.ForceOwnership,
 		client.FieldOwner(ManagerName),
 	}
 
+	desired = desired.DeepCopy()
 	gvk, err := r.getGvk(desired)
 	if err != nil {
-		return err
+		return controllerutil.OperationResultNone, err
 	}
 	desired.SetGroupVersionKind(gvk)
 
-	// screen the bug with creationTimestamp https://github.com/kubernetes/kubernetes/issues/116861
-	unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	unstr, err := toApplyUnstructured(desired)
 	if err != nil {
-		return err
+		return controllerutil.OperationResultNone, err
 	}
 
-	meta := unstr["metadata"].(map[string]interface{})
-	delete(meta, "creationTimestamp")
-	delete(unstr, "status")
-	unstr["metadata"] = meta
-
 	obj := &unstructured.Unstructured{
 		Object: unstr,
 	}
 
-	return r.Client.Patch(ctx, obj, client.Apply, patchOpts...)
+	if err := r.Client.Patch(ctx, obj, client.Apply, patchOpts...); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	return applyResult(created, current.ResourceVersion, obj.GetResourceVersion()), nil
+}
+
+// reconcileChildResourceThreeWayMerge patches the child like kubectl apply:
+// fields dropped from the template since the last applied child are removed,
+// fields set by others are kept.
+func (r *MyResourceReconciler) reconcileChildResourceThreeWayMerge(ctx context.Context, desired *samplev1.MyChildResource) (controllerutil.OperationResult, error) {
+	current, created, err := createChildResource(ctx, r.Client, desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	applied := desired.DeepCopy()
+	delete(applied.Annotations, AnnotationLastApplied)
+	unstr, err := toApplyUnstructured(applied)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	lastApplied, err := json.Marshal(unstr)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	applied.Annotations[AnnotationLastApplied] = string(lastApplied)
+	if unstr, err = toApplyUnstructured(applied); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	modified, err := json.Marshal(unstr)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	live, err := json.Marshal(current)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+
+	original := []byte(current.Annotations[AnnotationLastApplied])
+	if len(original) == 0 {
+		original = []byte("{}")
+	}
+	patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, live)
+	if err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	if string(patch) == "{}" {
+		return createdOr(created, controllerutil.OperationResultNone), nil
+	}
+	if err := r.Client.Patch(ctx, current, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return controllerutil.OperationResultNone, err
+	}
+	return createdOr(created, controllerutil.OperationResultUpdated), nil
+}
+
+// applyResult describes a server-side apply from the resource versions before
+// and after it.
+func applyResult(created bool, before, after string) controllerutil.OperationResult {
+	switch {
+	case created:
+		return controllerutil.OperationResultCreated
+	case before != after:
+		return controllerutil.OperationResultUpdated
+	default:
+		return controllerutil.OperationResultNone
+	}
+}
+
+// createdOr reports a child created by createChildResource as created even
+// though the strategy updated it right after.
+func createdOr(created bool, result controllerutil.OperationResult) controllerutil.OperationResult {
+	if created {
+		return controllerutil.OperationResultCreated
+	}
+	return result
+}
+
+// coalesceChildResource merges desired into current. Values set in desired
+// win, everything else is kept from current.
+func coalesceChildResource(current, desired *samplev1.MyChildResource) error {
+	c, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return err
+	}
+
+	d, err := toApplyUnstructured(desired)
+	if err != nil {
+		return err
+	}
+
+	result := chartutil.CoalesceTables(d, c)
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(result, current)
+}
+
+// toApplyUnstructured converts a desired object to its unstructured form
+// without status and the empty creationTimestamp.
+func toApplyUnstructured(obj client.Object) (map[string]interface{}, error) {
+	// screen the bug with creationTimestamp https://github.com/kubernetes/kubernetes/issues/116861
+	unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := unstr["metadata"].(map[string]interface{})
+	delete(metadata, "creationTimestamp")
+	delete(unstr, "status")
+	unstr["metadata"] = metadata
+
+	return unstr, nil
 }
 
 func (r *MyResourceReconciler) getGvk(obj client.Object) (schema.GroupVersionKind, error) {
@@ -345,14 +637,25 @@ func (r *MyResourceReconciler) getGvk(obj client.Object) (schema.GroupVersionKin
 
 }
 
-func CreateChildResource(ctx context.Context, c client.Client, name string) error {
-	resource := getMyChildResource(name)
+// CreateChildResource creates an initial object for desired, tracked and
+// controlled by the same parent, unless it already exists. Whether an existing
+// object may be managed is decided by the adoption policy before any strategy
+// runs.
+func CreateChildResource(ctx context.Context, c client.Client, desired *samplev1.MyChildResource) error {
+	_, _, err := createChildResource(ctx, c, desired)
+	return err
+}
+
+// createChildResource is CreateChildResource returning the live object and
+// whether it was created.
+func createChildResource(ctx context.Context, c client.Client, desired *samplev1.MyChildResource) (*samplev1.MyChildResource, bool, error) {
+	resource := getMyChildResource(desired.Namespace, desired.Name)
 
 	if err := c.Get(
 		ctx, client.ObjectKeyFromObject(resource), resource,
 	); err != nil {
 		if !apierrors.IsNotFound(err) {
-			return err
+			return nil, false, err
 		}
 		resource.Annotations = map[string]string{
 			"init-annotation": "yes",
@@ -360,18 +663,39 @@ func CreateChildResource(ctx context.Context, c client.Client, name string) erro
 		resource.Labels = map[string]string{
 			"init-label": "yes",
 		}
-		c.Create(ctx, resource)
+		for _, key := range []string{LabelParentName, LabelParentNamespace, LabelParentUID} {
+			if value, ok := desired.Labels[key]; ok {
+				resource.Labels[key] = value
+			}
+		}
+		resource.OwnerReferences = desired.OwnerReferences
+		if err := c.Create(ctx, resource); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return nil, false, err
+			}
+			// Created concurrently: report the live object rather than
+			// the stub that was never stored.
+			existing := getMyChildResource(desired.Namespace, desired.Name)
+			if err := c.Get(ctx, client.ObjectKeyFromObject(existing), existing); err != nil {
+				return nil, false, err
+			}
+			return existing, false, nil
+		}
+		return resource, true, nil
 	}
-	return nil
+	return resource, false, nil
 }
 
-func getMyChildResource(name string) *samplev1.MyChildResource {
+// getMyChildResource returns a new MyChildResource with the given key. Every
+// call allocates fresh maps: objects are decoded into in place, so sharing a
+// map between calls would leak labels between concurrent reconciles.
+func getMyChildResource(namespace, name string) *samplev1.MyChildResource {
 	return &samplev1.MyChildResource{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
-			Namespace:   "default",
+			Namespace:   namespace,
 			Annotations: map[string]string{},
-			Labels:      LabelsOrigin,
+			Labels:      map[string]string{},
 		},
 	}
 
@@ -22,9 +22,18 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	samplev1 "k8s-controller.ad/api/v1"
@@ -81,4 +90,244 @@ var _ = Describe("MyResource Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When recording revisions", func() {
+		const resourceName = "test-resource-revisions"
+		const childName = "test-resource-revisions-child"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		var controllerReconciler *MyResourceReconciler
+
+		reconcileParent := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		updateParent := func(mutate func(*samplev1.MyResource)) {
+			resource := &samplev1.MyResource{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			mutate(resource)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		}
+		setFoo := func(foo string) {
+			updateParent(func(resource *samplev1.MyResource) {
+				resource.Spec.Children[0].Spec.Foo = foo
+			})
+		}
+		getParent := func() *samplev1.MyResource {
+			resource := &samplev1.MyResource{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			return resource
+		}
+		getChildFoo := func() string {
+			child := &samplev1.MyChildResource{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: childName, Namespace: "default"}, child)).To(Succeed())
+			return child.Spec.Foo
+		}
+		listRevisions := func() []appsv1.ControllerRevision {
+			list := &appsv1.ControllerRevisionList{}
+			Expect(k8sClient.List(ctx, list,
+				client.InNamespace("default"),
+				client.MatchingLabels{LabelParentName: resourceName},
+			)).To(Succeed())
+			return list.Items
+		}
+
+		BeforeEach(func() {
+			controllerReconciler = &MyResourceReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("creating a MyResource with a single child template")
+			resource := &samplev1.MyResource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: samplev1.MyResourceSpec{
+					Strategy: samplev1.ApplyStrategySSA,
+					Children: []samplev1.ChildTemplate{{
+						Name: childName,
+						Spec: samplev1.MyChildResourceSpec{Foo: "first"},
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			By("Cleanup the parent, its child and its revisions")
+			Expect(k8sClient.Delete(ctx, getParent())).To(Succeed())
+			child := getMyChildResource("default", childName)
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, child))).To(Succeed())
+			Expect(k8sClient.DeleteAllOf(ctx, &appsv1.ControllerRevision{},
+				client.InNamespace("default"),
+				client.MatchingLabels{LabelParentName: resourceName},
+			)).To(Succeed())
+		})
+
+		It("should record one revision per distinct child set", func() {
+			reconcileParent()
+			first := getParent().Status
+			Expect(first.Revision).To(Equal(int64(1)))
+			Expect(getChildFoo()).To(Equal("first"))
+
+			By("changing the child template")
+			setFoo("second")
+			reconcileParent()
+			Expect(getParent().Status.Revision).To(Equal(int64(2)))
+			Expect(getChildFoo()).To(Equal("second"))
+
+			By("reverting the child template")
+			setFoo("first")
+			reconcileParent()
+			status := getParent().Status
+			Expect(status.Revision).To(Equal(int64(3)))
+			Expect(status.CurrentRevision).To(Equal(first.CurrentRevision))
+			Expect(listRevisions()).To(HaveLen(2))
+		})
+
+		It("should apply a recorded revision while rollbackTo is set", func() {
+			reconcileParent()
+			setFoo("second")
+			reconcileParent()
+			Expect(getChildFoo()).To(Equal("second"))
+
+			By("rolling back to the first revision")
+			updateParent(func(resource *samplev1.MyResource) {
+				resource.Spec.RollbackTo = &samplev1.RollbackConfig{Revision: 1}
+			})
+			reconcileParent()
+			Expect(getChildFoo()).To(Equal("first"))
+			ready := meta.FindStatusCondition(getParent().Status.Conditions, samplev1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal("RolledBack"))
+
+			By("clearing rollbackTo")
+			updateParent(func(resource *samplev1.MyResource) {
+				resource.Spec.RollbackTo = nil
+			})
+			reconcileParent()
+			Expect(getChildFoo()).To(Equal("second"))
+		})
+
+		It("should report a rollback to an unknown revision", func() {
+			updateParent(func(resource *samplev1.MyResource) {
+				resource.Spec.RollbackTo = &samplev1.RollbackConfig{Revision: 42}
+			})
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).To(HaveOccurred())
+
+			ready := meta.FindStatusCondition(getParent().Status.Conditions, samplev1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("RollbackRevisionNotFound"))
+		})
+
+		It("should keep at most revisionHistoryLimit old revisions", func() {
+			updateParent(func(resource *samplev1.MyResource) {
+				resource.Spec.RevisionHistoryLimit = ptr.To[int32](1)
+			})
+			for _, foo := range []string{"first", "second", "third", "fourth"} {
+				setFoo(foo)
+				reconcileParent()
+			}
+
+			revisions := listRevisions()
+			Expect(revisions).To(HaveLen(2))
+			for _, rev := range revisions {
+				Expect(rev.Revision).To(BeNumerically(">=", 3))
+			}
+		})
+	})
+
+	Context("When a parent sets no strategy", func() {
+		ctx := context.Background()
+
+		It("should apply its children with the default strategy of the manager", func() {
+			controllerReconciler := &MyResourceReconciler{
+				Client:          k8sClient,
+				Scheme:          k8sClient.Scheme(),
+				DefaultStrategy: samplev1.ApplyStrategyPatch,
+			}
+			parent := &samplev1.MyResource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-resource-default-strategy"},
+				Spec: samplev1.MyResourceSpec{Children: []samplev1.ChildTemplate{
+					{Name: "test-resource-default-strategy-child"},
+				}},
+			}
+			Expect(k8sClient.Create(ctx, parent)).To(Succeed())
+			child := getMyChildResource("default", "test-resource-default-strategy-child")
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, parent))).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, child))).To(Succeed())
+			})
+			Expect(parent.Spec.Strategy).To(BeEmpty())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(parent)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(child), child)).To(Succeed())
+			Expect(child.Annotations).To(HaveKeyWithValue(AnnotationStrategy, string(samplev1.ApplyStrategyPatch)))
+		})
+	})
+
+	Context("When creating children", func() {
+		ctx := context.Background()
+
+		It("should apply the whole template of a new child with the Suggested strategy", func() {
+			controllerReconciler := &MyResourceReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			desired := getMyChildResource("default", "test-resource-suggested-child")
+			desired.Labels["team"] = "a"
+			desired.Annotations[AnnotationStrategy] = string(samplev1.ApplyStrategySuggested)
+			desired.Spec = samplev1.MyChildResourceSpec{Foo: "desired", FooList: []string{"a"}}
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, desired))).To(Succeed())
+			})
+
+			result, err := controllerReconciler.applyChild(ctx, desired.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(controllerutil.OperationResultCreated))
+
+			child := &samplev1.MyChildResource{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(desired), child)).To(Succeed())
+			Expect(child.Labels).To(HaveKeyWithValue("team", "a"))
+			Expect(child.Spec.Foo).To(Equal("desired"))
+			Expect(child.Spec.FooList).To(Equal([]string{"a"}))
+		})
+
+		It("should report the live child when it is created concurrently", func() {
+			scheme := runtime.NewScheme()
+			utilruntime.Must(samplev1.AddToScheme(scheme))
+			live := getMyChildResource("default", "test-resource-concurrent-child")
+			live.Labels["owner"] = "other"
+			base := fake.NewClientBuilder().WithScheme(scheme).WithObjects(live).Build()
+			gets := 0
+			racing := interceptor.NewClient(base, interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if gets++; gets == 1 {
+						return errors.NewNotFound(samplev1.GroupVersion.WithResource("mychildresources").GroupResource(), key.Name)
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			})
+
+			current, created, err := createChildResource(ctx, racing, live.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created).To(BeFalse())
+			Expect(current.ResourceVersion).NotTo(BeEmpty())
+			Expect(current.Labels).To(HaveKeyWithValue("owner", "other"))
+		})
+	})
 })
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NamespaceAllowList maps the namespace of a parent to the namespaces its
+// children may be created in. A parent may always target its own namespace.
+// The key "*" applies to parents in every namespace and the value "*" allows
+// every namespace.
+type NamespaceAllowList map[string][]string
+
+// ParseNamespaceAllowList parses an allow-list of the form
+// "parent-ns=child-ns,other-ns;*=shared".
+func ParseNamespaceAllowList(s string) (NamespaceAllowList, error) {
+	allowList := NamespaceAllowList{}
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parent, children, ok := strings.Cut(entry, "=")
+		parent = strings.TrimSpace(parent)
+		if !ok || parent == "" {
+			return nil, fmt.Errorf("invalid namespace allow-list entry %q, expected parent=child[,child]", entry)
+		}
+		for _, child := range strings.Split(children, ",") {
+			if child = strings.TrimSpace(child); child != "" {
+				allowList[parent] = append(allowList[parent], child)
+			}
+		}
+	}
+	return allowList, nil
+}
+
+// Allowed reports whether a parent in parentNamespace may create children in
+// childNamespace.
+func (l NamespaceAllowList) Allowed(parentNamespace, childNamespace string) bool {
+	if parentNamespace == childNamespace {
+		return true
+	}
+	for _, key := range []string{parentNamespace, "*"} {
+		allowed := l[key]
+		if slices.Contains(allowed, "*") || slices.Contains(allowed, childNamespace) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// EventFilterOptions configures which watch events trigger a reconcile.
+type EventFilterOptions struct {
+	// Disabled lets every event through, as without any predicate.
+	Disabled bool
+	// LabelKeys are the parent labels whose changes trigger a reconcile even
+	// though the generation did not change.
+	LabelKeys []string
+	// AnnotationKeys are the parent annotations whose changes trigger a
+	// reconcile even though the generation did not change.
+	AnnotationKeys []string
+}
+
+// AnnotationReconcileRequestedAt requests a reconcile of a MyResource when
+// its value changes, whatever the event filters. kubectl myresource
+// reconcile sets it to the current time.
+const AnnotationReconcileRequestedAt = "sample.k8s-controller.ad/reconcile-requested-at"
+
+// parentPredicate filters the events of MyResources. Updates are only
+// accepted when the generation, AnnotationReconcileRequestedAt or one of the
+// configured labels or annotations changed, so status-only and unrelated
+// metadata updates are dropped.
+func (o EventFilterOptions) parentPredicate() predicate.Predicate {
+	if o.Disabled {
+		return countingPredicate{kind: "MyResource", Predicate: predicate.Funcs{}}
+	}
+	annotationKeys := append(slices.Clone(o.AnnotationKeys), AnnotationReconcileRequestedAt)
+	return countingPredicate{kind: "MyResource", Predicate: predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		keysChanged(o.LabelKeys, client.Object.GetLabels),
+		keysChanged(annotationKeys, client.Object.GetAnnotations),
+	)}
+}
+
+// childPredicate filters the events of MyChildResources. Updates that only
+// touch the status of a child are dropped, metadata changes are kept since the
+// tracking labels and owner references decide which parent is enqueued.
+func (o EventFilterOptions) childPredicate() predicate.Predicate {
+	if o.Disabled {
+		return countingPredicate{kind: "MyChildResource", Predicate: predicate.Funcs{}}
+	}
+	return countingPredicate{kind: "MyChildResource", Predicate: predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld == nil || e.ObjectNew == nil || !statusOnlyUpdate(e.ObjectOld, e.ObjectNew)
+		},
+	}}
+}
+
+// keysChanged accepts updates changing the value of one of keys in the map
+// returned by get.
+func keysChanged(keys []string, get func(client.Object) map[string]string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			oldValues, newValues := get(e.ObjectOld), get(e.ObjectNew)
+			for _, key := range keys {
+				oldValue, oldOk := oldValues[key]
+				newValue, newOk := newValues[key]
+				if oldOk != newOk || oldValue != newValue {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// statusOnlyUpdate reports whether nothing but the status and the bookkeeping
+// metadata differs between old and new.
+func statusOnlyUpdate(old, new client.Object) bool {
+	return old.GetGeneration() == new.GetGeneration() &&
+		equality.Semantic.DeepEqual(old.GetLabels(), new.GetLabels()) &&
+		equality.Semantic.DeepEqual(old.GetAnnotations(), new.GetAnnotations()) &&
+		equality.Semantic.DeepEqual(old.GetOwnerReferences(), new.GetOwnerReferences()) &&
+		equality.Semantic.DeepEqual(old.GetFinalizers(), new.GetFinalizers()) &&
+		equality.Semantic.DeepEqual(old.GetDeletionTimestamp(), new.GetDeletionTimestamp())
+}
+
+// countingPredicate counts the events accepted and filtered by Predicate in
+// the eventsTotal metric.
+type countingPredicate struct {
+	predicate.Predicate
+	kind string
+}
+
+func (p countingPredicate) Create(e event.CreateEvent) bool {
+	return p.count("create", p.Predicate.Create(e))
+}
+
+func (p countingPredicate) Delete(e event.DeleteEvent) bool {
+	return p.count("delete", p.Predicate.Delete(e))
+}
+
+func (p countingPredicate) Update(e event.UpdateEvent) bool {
+	return p.count("update", p.Predicate.Update(e))
+}
+
+func (p countingPredicate) Generic(e event.GenericEvent) bool {
+	return p.count("generic", p.Predicate.Generic(e))
+}
+
+func (p countingPredicate) count(eventType string, accepted bool) bool {
+	result := "filtered"
+	if accepted {
+		result = "accepted"
+	}
+	eventsTotal.WithLabelValues(p.kind, eventType, result).Inc()
+	return accepted
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("Event filters", func() {
+	parent := func(generation int64, labels, annotations map[string]string) *samplev1.MyResource {
+		return &samplev1.MyResource{ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-resource-events",
+			Namespace:   "default",
+			Generation:  generation,
+			Labels:      labels,
+			Annotations: annotations,
+		}}
+	}
+
+	It("should only accept parent updates changing the generation or a watched key", func() {
+		filtered := testutil.ToFloat64(eventsTotal.WithLabelValues("MyResource", "update", "filtered"))
+		p := EventFilterOptions{AnnotationKeys: []string{"reconcile"}}.parentPredicate()
+
+		Expect(p.Create(event.CreateEvent{Object: parent(1, nil, nil)})).To(BeTrue())
+		Expect(p.Update(event.UpdateEvent{
+			ObjectOld: parent(1, nil, nil),
+			ObjectNew: parent(2, nil, nil),
+		})).To(BeTrue())
+		Expect(p.Update(event.UpdateEvent{
+			ObjectOld: parent(2, nil, nil),
+			ObjectNew: parent(2, nil, map[string]string{"reconcile": "now"}),
+		})).To(BeTrue())
+		Expect(p.Update(event.UpdateEvent{
+			ObjectOld: parent(2, nil, nil),
+			ObjectNew: parent(2, nil, map[string]string{AnnotationReconcileRequestedAt: "2025-01-01T00:00:00Z"}),
+		})).To(BeTrue())
+		Expect(p.Update(event.UpdateEvent{
+			ObjectOld: parent(2, nil, nil),
+			ObjectNew: parent(2, map[string]string{"team": "a"}, map[string]string{"note": "x"}),
+		})).To(BeFalse())
+
+		Expect(testutil.ToFloat64(eventsTotal.WithLabelValues("MyResource", "update", "filtered"))).
+			To(Equal(filtered + 1))
+	})
+
+	It("should drop status-only child updates", func() {
+		oldChild := getMyChildResource("default", "test-resource-events-child")
+		oldChild.Generation = 1
+		newChild := oldChild.DeepCopy()
+		newChild.ResourceVersion = "2"
+		newChild.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "status-writer", Subresource: "status"}}
+		p := EventFilterOptions{}.childPredicate()
+
+		Expect(p.Update(event.UpdateEvent{ObjectOld: oldChild, ObjectNew: newChild})).To(BeFalse())
+
+		newChild.Labels[LabelParentName] = "test-resource-events"
+		Expect(p.Update(event.UpdateEvent{ObjectOld: oldChild, ObjectNew: newChild})).To(BeTrue())
+	})
+
+	It("should accept every event when disabled", func() {
+		p := EventFilterOptions{Disabled: true}.parentPredicate()
+		Expect(p.Update(event.UpdateEvent{
+			ObjectOld: parent(1, nil, nil),
+			ObjectNew: parent(1, nil, nil),
+		})).To(BeTrue())
+	})
+})
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+const (
+	// AnnotationPrune set to "false" on a child keeps it when it is no longer desired.
+	AnnotationPrune = "sample.k8s-controller.ad/prune"
+)
+
+// childReference returns the inventory entry of a child.
+func childReference(child *samplev1.MyChildResource) samplev1.ChildReference {
+	return samplev1.ChildReference{
+		Cluster:   clusterOf(child),
+		Namespace: child.Namespace,
+		Name:      child.Name,
+	}
+}
+
+// propagationPolicy returns the propagation policy used to prune children of parent.
+func propagationPolicy(parent *samplev1.MyResource) metav1.DeletionPropagation {
+	if parent.Spec.Prune == nil || parent.Spec.Prune.PropagationPolicy == "" {
+		return metav1.DeletePropagationBackground
+	}
+	return parent.Spec.Prune.PropagationPolicy
+}
+
+// pruneChildren deletes the children recorded in the inventory of parent that
+// are no longer part of desired, in the local or their remote cluster.
+// Children that parent does not control or that opted out with AnnotationPrune
+// are left in place.
+func (r *MyResourceReconciler) pruneChildren(ctx context.Context, parent *samplev1.MyResource, desired []*samplev1.MyChildResource) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	keep := make(map[samplev1.ChildReference]struct{}, len(desired))
+	for _, child := range desired {
+		keep[childReference(child)] = struct{}{}
+	}
+
+	for _, ref := range parent.Status.Inventory {
+		if _, ok := keep[ref]; ok {
+			continue
+		}
+
+		target, err := r.forCluster(ctx, parent, ref.Cluster)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("failed to reach cluster %s", ref.Cluster))
+		}
+		child := &samplev1.MyChildResource{}
+		if err := target.Client.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, child); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			continue
+		}
+		if !isControlledByParent(child, parent) {
+			log.Info("Not pruning child resource controlled by another owner", "namespace", ref.Namespace, "name", ref.Name)
+			continue
+		}
+		if child.Annotations[AnnotationPrune] == "false" {
+			log.Info("Not pruning child resource opted out of pruning", "namespace", ref.Namespace, "name", ref.Name)
+			continue
+		}
+
+		log.Info("Pruning child resource", "cluster", ref.Cluster, "namespace", ref.Namespace, "name", ref.Name)
+		if err := target.Client.Delete(ctx, child,
+			client.PropagationPolicy(propagationPolicy(parent)),
+			client.Preconditions{UID: &child.UID},
+		); client.IgnoreNotFound(err) != nil {
+			r.eventf(parent, corev1.EventTypeWarning, EventReasonChildFailed,
+				"Failed to prune child resource %s: %v", inventoryKey(ref), err)
+			return err
+		}
+		r.eventf(parent, corev1.EventTypeNormal, EventReasonChildPruned, "Pruned child resource %s", inventoryKey(ref))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("Child pruning", func() {
+	const resourceName = "test-resource-prune"
+	const keptName = "test-resource-prune-kept"
+	const prunedName = "test-resource-prune-removed"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	var controllerReconciler *MyResourceReconciler
+
+	reconcileParent := func() *samplev1.MyResource {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		resource := &samplev1.MyResource{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		return resource
+	}
+	dropChild := func(name string) {
+		resource := &samplev1.MyResource{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		children := resource.Spec.Children[:0]
+		for _, tmpl := range resource.Spec.Children {
+			if tmpl.Name != name {
+				children = append(children, tmpl)
+			}
+		}
+		resource.Spec.Children = children
+		Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+	}
+	childExists := func(name string) bool {
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, &samplev1.MyChildResource{})
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	BeforeEach(func() {
+		controllerReconciler = &MyResourceReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+
+		By("creating a MyResource with two children")
+		resource := &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: samplev1.MyResourceSpec{
+				Strategy: samplev1.ApplyStrategySSA,
+				// envtest runs no garbage collector, so only background
+				// deletion removes the pruned child.
+				Prune: &samplev1.Prune{
+					PropagationPolicy: metav1.DeletePropagationBackground,
+				},
+				Children: []samplev1.ChildTemplate{
+					{Name: keptName},
+					{Name: prunedName},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the parent, its children and its revisions")
+		Expect(k8sClient.DeleteAllOf(ctx, &samplev1.MyResource{}, client.InNamespace("default"))).To(Succeed())
+		for _, name := range []string{keptName, prunedName} {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, getMyChildResource("default", name)))).To(Succeed())
+		}
+		Expect(k8sClient.DeleteAllOf(ctx, &appsv1.ControllerRevision{}, client.InNamespace("default"))).To(Succeed())
+	})
+
+	It("should delete children removed from the spec", func() {
+		resource := reconcileParent()
+		Expect(resource.Status.Inventory).To(ConsistOf(
+			samplev1.ChildReference{Namespace: "default", Name: keptName},
+			samplev1.ChildReference{Namespace: "default", Name: prunedName},
+		))
+
+		dropChild(prunedName)
+		resource = reconcileParent()
+		Expect(resource.Status.Inventory).To(ConsistOf(
+			samplev1.ChildReference{Namespace: "default", Name: keptName},
+		))
+		Expect(childExists(keptName)).To(BeTrue())
+		Eventually(func() bool { return childExists(prunedName) }).Should(BeFalse())
+	})
+
+	It("should keep children annotated with prune=false", func() {
+		reconcileParent()
+
+		child := &samplev1.MyChildResource{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: prunedName, Namespace: "default"}, child)).To(Succeed())
+		child.Annotations[AnnotationPrune] = "false"
+		Expect(k8sClient.Update(ctx, child)).To(Succeed())
+
+		dropChild(prunedName)
+		resource := reconcileParent()
+		Expect(resource.Status.Inventory).To(HaveLen(1))
+		Expect(childExists(prunedName)).To(BeTrue())
+	})
+})
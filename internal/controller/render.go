@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+// RenderChildren renders the child templates of parent into the MyChildResource
+// objects applied by the reconciler, sorted by cluster, namespace and name. The
+// strategy each child is applied with is recorded in its AnnotationStrategy
+// annotation and its remote cluster, if any, in AnnotationCluster. Children
+// setting no strategy are applied with defaultStrategy, the DefaultStrategy of
+// the reconciler, or ApplyStrategySuggested when empty. The specs of the
+// children are defaulted like the API server defaults them.
+func RenderChildren(parent *samplev1.MyResource, defaultStrategy samplev1.ApplyStrategy) ([]*samplev1.MyChildResource, error) {
+	children := make([]*samplev1.MyChildResource, 0, len(parent.Spec.Children))
+	seen := make(map[string]struct{}, len(parent.Spec.Children))
+
+	for _, tmpl := range parent.Spec.Children {
+		if tmpl.Name == "" {
+			return nil, fmt.Errorf("child template without a name")
+		}
+		namespace := tmpl.Namespace
+		if namespace == "" {
+			namespace = parent.Namespace
+		}
+		key := namespace + "/" + tmpl.Name
+		if tmpl.Cluster != "" {
+			key = tmpl.Cluster + ":" + key
+		}
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("duplicate child template %q", key)
+		}
+		seen[key] = struct{}{}
+
+		strategy := tmpl.Strategy
+		if strategy == "" {
+			strategy = parent.Spec.Strategy
+		}
+		if strategy == "" {
+			strategy = defaultStrategy
+		}
+		if strategy == "" {
+			strategy = samplev1.ApplyStrategySuggested
+		}
+
+		child := getMyChildResource(namespace, tmpl.Name)
+		child.Labels = maps.Clone(tmpl.Labels)
+		child.Annotations = maps.Clone(tmpl.Annotations)
+		if child.Annotations == nil {
+			child.Annotations = map[string]string{}
+		}
+		child.Annotations[AnnotationStrategy] = string(strategy)
+		if tmpl.Cluster != "" {
+			child.Annotations[AnnotationCluster] = tmpl.Cluster
+		}
+		tmpl.Spec.DeepCopyInto(&child.Spec)
+		child.Spec.SetDefaults()
+
+		children = append(children, child)
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		if clusterOf(children[i]) != clusterOf(children[j]) {
+			return clusterOf(children[i]) < clusterOf(children[j])
+		}
+		if children[i].Namespace != children[j].Namespace {
+			return children[i].Namespace < children[j].Namespace
+		}
+		return children[i].Name < children[j].Name
+	})
+	return children, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("Rendering defaults", func() {
+	It("should apply the default strategy to children setting none", func() {
+		parent := &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-resource-render"},
+			Spec: samplev1.MyResourceSpec{Children: []samplev1.ChildTemplate{
+				{Name: "defaulted"},
+				{Name: "explicit", Strategy: samplev1.ApplyStrategyPatch},
+			}},
+		}
+
+		children, err := RenderChildren(parent, samplev1.ApplyStrategySSA)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(children[0].Annotations[AnnotationStrategy]).To(Equal(string(samplev1.ApplyStrategySSA)))
+		Expect(children[1].Annotations[AnnotationStrategy]).To(Equal(string(samplev1.ApplyStrategyPatch)))
+
+		children, err = RenderChildren(parent, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(children[0].Annotations[AnnotationStrategy]).To(Equal(string(samplev1.ApplyStrategySuggested)))
+	})
+
+	It("should default the specs of children like the API server", func() {
+		parent := &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-resource-render"},
+			Spec: samplev1.MyResourceSpec{Children: []samplev1.ChildTemplate{
+				{Name: "defaulted"},
+				{Name: "explicit", Spec: samplev1.MyChildResourceSpec{
+					FooMap:          map[string]string{"key": "value"},
+					FooValueDefault: "explicit",
+				}},
+			}},
+		}
+
+		children, err := RenderChildren(parent, samplev1.ApplyStrategySuggested)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(children[0].Spec).To(Equal(samplev1.MyChildResourceSpec{
+			FooMap:          map[string]string{},
+			FooValueDefault: samplev1.DefaultFooValue,
+		}))
+		Expect(children[1].Spec.FooMap).To(Equal(map[string]string{"key": "value"}))
+		Expect(children[1].Spec.FooValueDefault).To(Equal("explicit"))
+		Expect(parent.Spec.Children[0].Spec.FooMap).To(BeNil())
+	})
+})
+
+var _ = Describe("Resync", func() {
+	It("should change the resync interval of a running controller", func() {
+		var unset *Resync
+		Expect(unset.Interval()).To(Equal(DefaultResyncInterval))
+
+		resync := NewResync(time.Minute)
+		Expect(resync.Interval()).To(Equal(time.Minute))
+		resync.SetInterval(time.Second)
+		Expect(resync.Interval()).To(Equal(time.Second))
+	})
+})
@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DefaultResyncInterval is the delay after which a reconciled MyResource is
+// reconciled again unless configured otherwise.
+const DefaultResyncInterval = 5 * time.Second
+
+// Resync holds the delay after which a reconciled MyResource is reconciled
+// again. It may be changed while the controller runs.
+type Resync struct {
+	interval atomic.Int64
+}
+
+// NewResync returns a Resync of interval.
+func NewResync(interval time.Duration) *Resync {
+	r := &Resync{}
+	r.SetInterval(interval)
+	return r
+}
+
+// Interval returns the resync interval, DefaultResyncInterval when r is nil
+// or unset.
+func (r *Resync) Interval() time.Duration {
+	if r == nil {
+		return DefaultResyncInterval
+	}
+	if interval := time.Duration(r.interval.Load()); interval > 0 {
+		return interval
+	}
+	return DefaultResyncInterval
+}
+
+// SetInterval changes the resync interval of the following reconciles.
+func (r *Resync) SetInterval(interval time.Duration) {
+	r.interval.Store(int64(interval))
+}
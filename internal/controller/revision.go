@@ -0,0 +1,235 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+const (
+	// LabelParentName is set on every revision to the name of its MyResource.
+	LabelParentName = "sample.k8s-controller.ad/parent-name"
+	// LabelRevisionHash is set on every revision to the hash of its child set.
+	LabelRevisionHash = "sample.k8s-controller.ad/revision-hash"
+
+	defaultRevisionHistoryLimit = 10
+)
+
+// revisionData serializes a rendered child set for storage in a revision.
+func revisionData(children []*samplev1.MyChildResource) ([]byte, error) {
+	list := samplev1.MyChildResourceList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: samplev1.GroupVersion.String(),
+			Kind:       "MyChildResourceList",
+		},
+		Items: make([]samplev1.MyChildResource, 0, len(children)),
+	}
+	for _, child := range children {
+		list.Items = append(list.Items, *child)
+	}
+	return json.Marshal(list)
+}
+
+// revisionHash returns the short, label-safe hash of serialized revision data.
+func revisionHash(data []byte) string {
+	hasher := fnv.New32a()
+	hasher.Write(data)
+	return rand.SafeEncodeString(strconv.FormatUint(uint64(hasher.Sum32()), 10))
+}
+
+// childrenFromRevision returns the rendered child set stored in a revision,
+// defaulted like freshly rendered children since the revision may predate
+// the defaulting.
+func childrenFromRevision(rev *appsv1.ControllerRevision) ([]*samplev1.MyChildResource, error) {
+	list := samplev1.MyChildResourceList{}
+	if err := json.Unmarshal(rev.Data.Raw, &list); err != nil {
+		return nil, fmt.Errorf("revision %s: %w", rev.Name, err)
+	}
+	children := make([]*samplev1.MyChildResource, 0, len(list.Items))
+	for i := range list.Items {
+		list.Items[i].Spec.SetDefaults()
+		children = append(children, &list.Items[i])
+	}
+	return children, nil
+}
+
+// DesiredChildren returns the children a reconcile of parent applies: the
+// child set of the revision named by spec.rollbackTo while it is set, the
+// rendered child templates otherwise, applying the children setting no
+// strategy with defaultStrategy like RenderChildren.
+func DesiredChildren(
+	ctx context.Context, c client.Reader, parent *samplev1.MyResource, defaultStrategy samplev1.ApplyStrategy,
+) ([]*samplev1.MyChildResource, error) {
+	if parent.Spec.RollbackTo == nil {
+		return RenderChildren(parent, defaultStrategy)
+	}
+	revisions, err := listRevisions(ctx, c, parent)
+	if err != nil {
+		return nil, err
+	}
+	rev := findRevision(revisions, parent.Spec.RollbackTo.Revision)
+	if rev == nil {
+		return nil, fmt.Errorf("revision %d not found", parent.Spec.RollbackTo.Revision)
+	}
+	return childrenFromRevision(rev)
+}
+
+// listRevisions returns the revisions controlled by parent, oldest first.
+func listRevisions(ctx context.Context, c client.Reader, parent *samplev1.MyResource) ([]*appsv1.ControllerRevision, error) {
+	list := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, list,
+		client.InNamespace(parent.Namespace),
+		client.MatchingLabels{LabelParentName: parent.Name},
+	); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*appsv1.ControllerRevision, 0, len(list.Items))
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], parent) {
+			revisions = append(revisions, &list.Items[i])
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// findRevision returns the revision with the given number, or nil.
+func findRevision(revisions []*appsv1.ControllerRevision, number int64) *appsv1.ControllerRevision {
+	for _, rev := range revisions {
+		if rev.Revision == number {
+			return rev
+		}
+	}
+	return nil
+}
+
+// syncRevision makes sure a revision holding children exists and is the newest
+// one. An existing revision with the same content is renumbered instead of
+// being recorded twice.
+func (r *MyResourceReconciler) syncRevision(
+	ctx context.Context,
+	parent *samplev1.MyResource,
+	revisions []*appsv1.ControllerRevision,
+	children []*samplev1.MyChildResource,
+) (*appsv1.ControllerRevision, error) {
+	data, err := revisionData(children)
+	if err != nil {
+		return nil, err
+	}
+	hash := revisionHash(data)
+
+	var next int64 = 1
+	if len(revisions) > 0 {
+		next = revisions[len(revisions)-1].Revision + 1
+	}
+
+	for _, rev := range revisions {
+		if rev.Labels[LabelRevisionHash] != hash || !bytes.Equal(rev.Data.Raw, data) {
+			continue
+		}
+		if rev.Revision == next-1 {
+			return rev, nil
+		}
+		rev.Revision = next
+		if err := r.Client.Update(ctx, rev); err != nil {
+			return nil, err
+		}
+		return rev, nil
+	}
+
+	rev := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      parent.Name + "-" + hash,
+			Namespace: parent.Namespace,
+			Labels: map[string]string{
+				LabelParentName:   parent.Name,
+				LabelRevisionHash: hash,
+			},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: next,
+	}
+	if err := controllerutil.SetControllerReference(parent, rev, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Client.Create(ctx, rev); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		// The cache has not seen the revision yet; reuse it if the content matches.
+		existing := &appsv1.ControllerRevision{}
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(rev), existing); err != nil {
+			return nil, err
+		}
+		if !metav1.IsControlledBy(existing, parent) || !bytes.Equal(existing.Data.Raw, data) {
+			return nil, fmt.Errorf("revision %s already exists with different content", rev.Name)
+		}
+		return existing, nil
+	}
+	return rev, nil
+}
+
+// truncateHistory deletes the oldest revisions beyond spec.revisionHistoryLimit.
+// The current revision and the one referenced by spec.rollbackTo are never deleted.
+func (r *MyResourceReconciler) truncateHistory(
+	ctx context.Context,
+	parent *samplev1.MyResource,
+	revisions []*appsv1.ControllerRevision,
+	current *appsv1.ControllerRevision,
+) error {
+	limit := defaultRevisionHistoryLimit
+	if parent.Spec.RevisionHistoryLimit != nil {
+		limit = int(*parent.Spec.RevisionHistoryLimit)
+	}
+
+	old := make([]*appsv1.ControllerRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		if rev.Name == current.Name {
+			continue
+		}
+		if parent.Spec.RollbackTo != nil && rev.Revision == parent.Spec.RollbackTo.Revision {
+			continue
+		}
+		old = append(old, rev)
+	}
+
+	for i := 0; i < len(old)-limit; i++ {
+		if err := r.Client.Delete(ctx, old[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
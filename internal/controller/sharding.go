@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/sharding"
+)
+
+// shardHandoffRetry is the delay after which a replica tries again to claim
+// a parent that another replica has not handed over yet.
+const shardHandoffRetry = 5 * time.Second
+
+// ownsParent reports whether this replica reconciles the parent key, which
+// is always the case without sharding.
+func (r *MyResourceReconciler) ownsParent(key client.ObjectKey) bool {
+	return r.Shard == nil || r.Shard.Owns(key.String())
+}
+
+// claimedParent reports whether parent carries the shard label of this
+// replica, which it has to remove once the parent moved to another replica.
+func (r *MyResourceReconciler) claimedParent(parent client.Object) bool {
+	return r.Shard != nil && parent.GetLabels()[sharding.LabelShard] == r.Shard.Identity()
+}
+
+// shardPredicate drops the events of parents assigned to other replicas,
+// unless they still have to be handed over by this one.
+func (r *MyResourceReconciler) shardPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return r.ownsParent(client.ObjectKeyFromObject(obj)) || r.claimedParent(obj)
+	})
+}
+
+// shardSource enqueues the parents owned or claimed by this replica whenever
+// the shard ring changes, so that parents moving to it are reconciled and
+// parents moving away are handed over right away instead of with their next
+// event.
+func (r *MyResourceReconciler) shardSource(reader client.Reader) source.Source {
+	return source.Func(func(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-r.Shard.Changed():
+				}
+
+				parents := &samplev1.MyResourceList{}
+				if err := reader.List(ctx, parents); err != nil {
+					ctrl.LoggerFrom(ctx).Error(err, "Unable to list the parents of the shard")
+					continue
+				}
+				for _, parent := range parents.Items {
+					if key := client.ObjectKeyFromObject(&parent); r.ownsParent(key) || r.claimedParent(&parent) {
+						queue.Add(reconcile.Request{NamespacedName: key})
+					}
+				}
+			}
+		}()
+		return nil
+	})
+}
+
+// claimShard labels parent with this replica, guarded by its resource
+// version so that two replicas never both claim it. It reports false while
+// the parent is still labelled by another replica of the ring, which hands it
+// over once it sees the parent moved.
+func (r *MyResourceReconciler) claimShard(ctx context.Context, parent *samplev1.MyResource) (bool, error) {
+	if r.Shard == nil || r.claimedParent(parent) {
+		return true, nil
+	}
+	if holder := parent.Labels[sharding.LabelShard]; holder != "" && r.Shard.IsMember(holder) {
+		ctrl.LoggerFrom(ctx).V(1).Info("Waiting for the shard of MyResource to hand it over", "holder", holder)
+		return false, nil
+	}
+	base := parent.DeepCopy()
+	if parent.Labels == nil {
+		parent.Labels = map[string]string{}
+	}
+	parent.Labels[sharding.LabelShard] = r.Shard.Identity()
+	return true, r.Client.Patch(ctx, parent, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+}
+
+// releaseShard removes the label of this replica from the parent key, which
+// hands the parent over to the replica it is assigned to now.
+func (r *MyResourceReconciler) releaseShard(ctx context.Context, key client.ObjectKey) error {
+	parent := &samplev1.MyResource{}
+	if err := r.Client.Get(ctx, key, parent); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !r.claimedParent(parent) {
+		return nil
+	}
+	base := parent.DeepCopy()
+	delete(parent.Labels, sharding.LabelShard)
+	err := r.Client.Patch(ctx, parent, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	return client.IgnoreNotFound(err)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/sharding"
+)
+
+var _ = Describe("Sharded MyResource", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		c      client.Client
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(func() { cancel() })
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		utilruntime.Must(samplev1.AddToScheme(scheme))
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "sharded",
+				Labels:    map[string]string{sharding.LabelShard: "replica-a"},
+			},
+		}).Build()
+	})
+
+	startReplica := func(identity string) *MyResourceReconciler {
+		shard := sharding.NewShard(c, sharding.Options{
+			Namespace:     "system",
+			Ring:          "myresource",
+			Identity:      identity,
+			RenewInterval: 10 * time.Millisecond,
+		})
+		go func() {
+			defer GinkgoRecover()
+			Expect(shard.Start(ctx)).To(Succeed())
+		}()
+		Eventually(shard.Members).Should(ContainElement(identity))
+		return &MyResourceReconciler{Client: c, Shard: shard}
+	}
+
+	getParent := func() *samplev1.MyResource {
+		parent := &samplev1.MyResource{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "sharded"}, parent)).To(Succeed())
+		return parent
+	}
+
+	It("should take a parent over only once the previous replica handed it over", func() {
+		a := startReplica("replica-a")
+		b := startReplica("replica-b")
+		Eventually(b.Shard.Members).Should(ContainElement("replica-a"))
+
+		By("waiting while the parent is claimed by a live replica")
+		claimed, err := b.claimShard(ctx, getParent())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimed).To(BeFalse())
+
+		By("handing the parent over")
+		Expect(a.releaseShard(ctx, client.ObjectKeyFromObject(getParent()))).To(Succeed())
+		Expect(getParent().Labels).NotTo(HaveKey(sharding.LabelShard))
+
+		claimed, err = b.claimShard(ctx, getParent())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimed).To(BeTrue())
+		Expect(getParent().Labels).To(HaveKeyWithValue(sharding.LabelShard, "replica-b"))
+
+		By("leaving the parents claimed by other replicas alone")
+		Expect(a.releaseShard(ctx, client.ObjectKeyFromObject(getParent()))).To(Succeed())
+		Expect(getParent().Labels).To(HaveKeyWithValue(sharding.LabelShard, "replica-b"))
+	})
+
+	It("should claim the parent of a replica without a live lease once", func() {
+		b := startReplica("replica-b")
+		replicaC := startReplica("replica-c")
+
+		stale := getParent()
+		claimed, err := b.claimShard(ctx, getParent())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimed).To(BeTrue())
+
+		_, err = replicaC.claimShard(ctx, stale)
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+		Expect(getParent().Labels).To(HaveKeyWithValue(sharding.LabelShard, "replica-b"))
+	})
+})
@@ -31,8 +31,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	samplev1 "k8s-controller.ad/api/v1"
+	samplev2 "k8s-controller.ad/api/v2"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -61,6 +64,8 @@ var _ = BeforeSuite(func() {
 	var err error
 	err = samplev1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = samplev2.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:scheme
 
@@ -83,6 +88,24 @@ var _ = BeforeSuite(func() {
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
+
+	// MyResource is stored as v2, so the v1 objects of the tests go through
+	// the conversion webhook envtest configures on the CRD.
+	By("serving the conversion webhook")
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
+	webhookServer := webhook.NewServer(webhook.Options{
+		Host:    webhookInstallOptions.LocalServingHost,
+		Port:    webhookInstallOptions.LocalServingPort,
+		CertDir: webhookInstallOptions.LocalServingCertDir,
+	})
+	webhookServer.Register("/convert", conversion.NewWebhookHandler(scheme.Scheme))
+	go func() {
+		defer GinkgoRecover()
+		Expect(webhookServer.Start(ctx)).To(Succeed())
+	}()
+	Eventually(func() error {
+		return webhookServer.StartedChecker()(nil)
+	}).Should(Succeed())
 })
 
 var _ = AfterSuite(func() {
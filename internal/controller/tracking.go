@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+// Owner references cannot cross namespaces or clusters, so every child also
+// carries labels pointing back to its parent. Children in the namespace of
+// their parent get a controller reference on top and are garbage collected
+// with it; children in other namespaces or remote clusters are deleted by the
+// reconciler before FinalizerChildren is removed from the parent.
+const (
+	// LabelParentNamespace is set on every child to the namespace of its MyResource.
+	LabelParentNamespace = "sample.k8s-controller.ad/parent-namespace"
+	// LabelParentUID is set on every child to the UID of its MyResource.
+	LabelParentUID = "sample.k8s-controller.ad/parent-uid"
+
+	// FinalizerChildren is set on parents with children in other namespaces.
+	FinalizerChildren = "sample.k8s-controller.ad/children"
+)
+
+// setParentTracking marks child as controlled by parent with the tracking
+// labels and, within the namespace and cluster of parent, a controller reference.
+func setParentTracking(parent *samplev1.MyResource, child *samplev1.MyChildResource, scheme *runtime.Scheme) error {
+	if child.Labels == nil {
+		child.Labels = map[string]string{}
+	}
+	child.Labels[LabelParentName] = parent.Name
+	child.Labels[LabelParentNamespace] = parent.Namespace
+	child.Labels[LabelParentUID] = string(parent.UID)
+
+	if child.Namespace != parent.Namespace || clusterOf(child) != "" {
+		return nil
+	}
+	return controllerutil.SetControllerReference(parent, child, scheme)
+}
+
+// clearParentTracking removes everything setParentTracking set for parent.
+func clearParentTracking(parent *samplev1.MyResource, child *samplev1.MyChildResource) {
+	if child.Labels[LabelParentUID] == string(parent.UID) {
+		delete(child.Labels, LabelParentName)
+		delete(child.Labels, LabelParentNamespace)
+		delete(child.Labels, LabelParentUID)
+	}
+
+	refs := make([]metav1.OwnerReference, 0, len(child.OwnerReferences))
+	for _, ref := range child.OwnerReferences {
+		if ref.UID != parent.UID {
+			refs = append(refs, ref)
+		}
+	}
+	child.OwnerReferences = refs
+}
+
+// controllerOf returns the UID and a description of whatever controls child,
+// looking at the controller reference first and the tracking labels second.
+func controllerOf(child client.Object) (types.UID, string, bool) {
+	if ref := metav1.GetControllerOf(child); ref != nil {
+		return ref.UID, fmt.Sprintf("%s %s", ref.Kind, ref.Name), true
+	}
+	labels := child.GetLabels()
+	if uid := labels[LabelParentUID]; uid != "" {
+		return types.UID(uid), fmt.Sprintf("MyResource %s/%s", labels[LabelParentNamespace], labels[LabelParentName]), true
+	}
+	return "", "", false
+}
+
+// isControlledByParent reports whether child is controlled by parent.
+func isControlledByParent(child client.Object, parent *samplev1.MyResource) bool {
+	uid, _, ok := controllerOf(child)
+	return ok && uid == parent.UID
+}
+
+// childToParent maps a child to the parent named by its tracking labels.
+func childToParent(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name, namespace := labels[LabelParentName], labels[LabelParentNamespace]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Namespace: namespace, Name: name},
+	}}
+}
+
+// needsChildrenFinalizer reports whether any of children lives outside the
+// namespace or cluster of parent and so is not garbage collected with it.
+func needsChildrenFinalizer(parent *samplev1.MyResource, children []*samplev1.MyChildResource) bool {
+	for _, child := range children {
+		if child.Namespace != parent.Namespace || clusterOf(child) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// finalizeChildren deletes the children of a deleted parent that live in other
+// namespaces or remote clusters and then removes FinalizerChildren. Children
+// of a remote cluster whose Secret is gone cannot be reached and are left.
+func (r *MyResourceReconciler) finalizeChildren(ctx context.Context, parent *samplev1.MyResource) error {
+	if !controllerutil.ContainsFinalizer(parent, FinalizerChildren) {
+		return nil
+	}
+
+	if err := r.deleteTrackedChildren(ctx, parent, parent.Namespace); err != nil {
+		return err
+	}
+	for _, name := range remoteClusters(parent) {
+		target, err := r.forCluster(ctx, parent, name)
+		if apierrors.IsNotFound(err) {
+			ctrl.LoggerFrom(ctx).Info("Leaving child resources of a deleted cluster secret", "cluster", name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := target.deleteTrackedChildren(ctx, parent, ""); err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(parent, FinalizerChildren)
+	return r.Client.Update(ctx, parent)
+}
+
+// deleteTrackedChildren deletes the children labeled with the UID of parent
+// outside of namespace, except for those opted out with AnnotationPrune.
+func (r *MyResourceReconciler) deleteTrackedChildren(ctx context.Context, parent *samplev1.MyResource, namespace string) error {
+	list := &samplev1.MyChildResourceList{}
+	if err := r.Client.List(ctx, list, client.MatchingLabels{LabelParentUID: string(parent.UID)}); err != nil {
+		return err
+	}
+	for i := range list.Items {
+		child := &list.Items[i]
+		if child.Namespace == namespace || child.Annotations[AnnotationPrune] == "false" {
+			continue
+		}
+		ctrl.LoggerFrom(ctx).Info("Deleting child resource of deleted parent", "namespace", child.Namespace, "name", child.Name)
+		if err := r.Client.Delete(ctx, child,
+			client.PropagationPolicy(propagationPolicy(parent)),
+			client.Preconditions{UID: &child.UID},
+		); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remoteClusters returns the sorted names of the remote clusters parent has
+// or had children in.
+func remoteClusters(parent *samplev1.MyResource) []string {
+	names := map[string]struct{}{}
+	for _, tmpl := range parent.Spec.Children {
+		if tmpl.Cluster != "" {
+			names[tmpl.Cluster] = struct{}{}
+		}
+	}
+	for _, ref := range parent.Status.Inventory {
+		if ref.Cluster != "" {
+			names[ref.Cluster] = struct{}{}
+		}
+	}
+	return slices.Sorted(maps.Keys(names))
+}
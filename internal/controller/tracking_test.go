@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("Namespace allow-list", func() {
+	It("should parse parent and child namespaces", func() {
+		allowList, err := ParseNamespaceAllowList("team-a=team-a-dev, team-a-prod; *=shared")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowList).To(Equal(NamespaceAllowList{
+			"team-a": {"team-a-dev", "team-a-prod"},
+			"*":      {"shared"},
+		}))
+
+		Expect(allowList.Allowed("team-a", "team-a")).To(BeTrue())
+		Expect(allowList.Allowed("team-a", "team-a-prod")).To(BeTrue())
+		Expect(allowList.Allowed("team-b", "shared")).To(BeTrue())
+		Expect(allowList.Allowed("team-b", "team-a-prod")).To(BeFalse())
+	})
+
+	It("should reject entries without a parent namespace", func() {
+		_, err := ParseNamespaceAllowList("=child")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseNamespaceAllowList("parent")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Cross-namespace children", func() {
+	const resourceName = "test-resource-cross-namespace"
+	const childName = "test-resource-cross-namespace-child"
+	const targetNamespace = "cross-namespace-target"
+
+	ctx := context.Background()
+
+	typeNamespacedName := types.NamespacedName{
+		Name:      resourceName,
+		Namespace: "default",
+	}
+	childKey := types.NamespacedName{
+		Name:      childName,
+		Namespace: targetNamespace,
+	}
+	var controllerReconciler *MyResourceReconciler
+
+	getParent := func() *samplev1.MyResource {
+		resource := &samplev1.MyResource{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		return resource
+	}
+
+	BeforeEach(func() {
+		controllerReconciler = &MyResourceReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+			NamespaceAllowList: NamespaceAllowList{
+				"default": {targetNamespace},
+			},
+		}
+
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: targetNamespace}}
+		Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, ns))).To(Succeed())
+
+		By("creating a MyResource with a child in another namespace")
+		resource := &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: "default",
+			},
+			Spec: samplev1.MyResourceSpec{
+				Strategy: samplev1.ApplyStrategySSA,
+				Children: []samplev1.ChildTemplate{{
+					Name:      childName,
+					Namespace: targetNamespace,
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("Cleanup the parent, its child and its revisions")
+		resource := &samplev1.MyResource{}
+		if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+			controllerutil.RemoveFinalizer(resource, FinalizerChildren)
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+		}
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, getMyChildResource(targetNamespace, childName)))).To(Succeed())
+		Expect(k8sClient.DeleteAllOf(ctx, &appsv1.ControllerRevision{}, client.InNamespace("default"))).To(Succeed())
+	})
+
+	It("should track the child with labels and map it back to the parent", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		parent := getParent()
+		Expect(controllerutil.ContainsFinalizer(parent, FinalizerChildren)).To(BeTrue())
+
+		child := &samplev1.MyChildResource{}
+		Expect(k8sClient.Get(ctx, childKey, child)).To(Succeed())
+		Expect(child.OwnerReferences).To(BeEmpty())
+		Expect(child.Labels).To(HaveKeyWithValue(LabelParentName, resourceName))
+		Expect(child.Labels).To(HaveKeyWithValue(LabelParentNamespace, "default"))
+		Expect(child.Labels).To(HaveKeyWithValue(LabelParentUID, string(parent.UID)))
+		Expect(isControlledByParent(child, parent)).To(BeTrue())
+
+		Expect(childToParent(ctx, child)).To(ConsistOf(reconcile.Request{NamespacedName: typeNamespacedName}))
+	})
+
+	It("should delete the child when the parent is deleted", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, childKey, &samplev1.MyChildResource{})).To(Succeed())
+
+		Expect(k8sClient.Delete(ctx, getParent())).To(Succeed())
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, childKey, &samplev1.MyChildResource{}))).To(BeTrue())
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &samplev1.MyResource{}))).To(BeTrue())
+	})
+
+	It("should refuse namespaces missing from the allow-list", func() {
+		controllerReconciler.NamespaceAllowList = nil
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).To(HaveOccurred())
+
+		ready := meta.FindStatusCondition(getParent().Status.Conditions, samplev1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Reason).To(Equal("NamespaceNotAllowed"))
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, childKey, &samplev1.MyChildResource{}))).To(BeTrue())
+	})
+})
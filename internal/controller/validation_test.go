@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var _ = Describe("CRD validation rules", func() {
+	expectInvalid := func(err error, message string) {
+		GinkgoHelper()
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "%v", err)
+		Expect(err.Error()).To(ContainSubstring(message))
+	}
+
+	newChild := func(name string, spec samplev1.MyChildResourceSpec) *samplev1.MyChildResource {
+		return &samplev1.MyChildResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       spec,
+		}
+	}
+
+	newParent := func(name string, children ...samplev1.ChildTemplate) *samplev1.MyResource {
+		return &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: samplev1.MyResourceSpec{
+				Strategy: samplev1.ApplyStrategySSA,
+				Children: children,
+			},
+		}
+	}
+
+	It("should reject duplicate fooList entries", func() {
+		err := k8sClient.Create(ctx, newChild("test-cel-list", samplev1.MyChildResourceSpec{
+			FooList: []string{"a", "b", "a"},
+		}))
+		expectInvalid(err, "spec.fooList: Invalid value: \"array\": fooList entries must be unique")
+	})
+
+	It("should reject fooMap keys that are not qualified names", func() {
+		err := k8sClient.Create(ctx, newChild("test-cel-map", samplev1.MyChildResourceSpec{
+			FooMap: map[string]string{"not a key": "value"},
+		}))
+		expectInvalid(err, "fooMap keys must be qualified names")
+	})
+
+	It("should admit valid children", func() {
+		child := newChild("test-cel-valid", samplev1.MyChildResourceSpec{
+			FooList: []string{"a", "b"},
+			FooMap:  map[string]string{"example.com/key": "value", "key_2": "value"},
+		})
+		Expect(k8sClient.Create(ctx, child)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, child)).To(Succeed())
+	})
+
+	It("should apply the child rules to child templates", func() {
+		err := k8sClient.Create(ctx, newParent("test-cel-template", samplev1.ChildTemplate{
+			Name: "child",
+			Spec: samplev1.MyChildResourceSpec{FooList: []string{"a", "a"}},
+		}))
+		expectInvalid(err, "spec.children[0].spec.fooList: Invalid value: \"array\": fooList entries must be unique")
+	})
+
+	It("should limit the number of children of a parent", func() {
+		children := make([]samplev1.ChildTemplate, 101)
+		for i := range children {
+			children[i].Name = fmt.Sprintf("child-%d", i)
+		}
+		err := k8sClient.Create(ctx, newParent("test-cel-max", children...))
+		expectInvalid(err, "spec.children: Too many: 101: must have at most 100 items")
+	})
+
+	It("should keep the strategy of a parent immutable", func() {
+		parent := newParent("test-cel-strategy", samplev1.ChildTemplate{Name: "child"})
+		Expect(k8sClient.Create(ctx, parent)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, parent))).To(Succeed())
+		})
+
+		By("allowing to change the strategy of a child template")
+		parent.Spec.Children[0].Strategy = samplev1.ApplyStrategyPatch
+		Expect(k8sClient.Update(ctx, parent)).To(Succeed())
+
+		By("rejecting to change the strategy of the parent")
+		parent.Spec.Strategy = samplev1.ApplyStrategyUpdate
+		err := k8sClient.Update(ctx, parent)
+		expectInvalid(err, "spec.strategy: Invalid value: \"object\": strategy is immutable, set the strategy of child templates instead")
+
+		By("rejecting to remove the strategy of the parent")
+		parent.Spec.Strategy = ""
+		err = k8sClient.Update(ctx, parent)
+		expectInvalid(err, "strategy is immutable")
+	})
+
+	It("should keep a parent without a strategy on the default strategy", func() {
+		parent := newParent("test-cel-default-strategy", samplev1.ChildTemplate{Name: "child"})
+		parent.Spec.Strategy = ""
+		Expect(k8sClient.Create(ctx, parent)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, parent))).To(Succeed())
+		})
+		Expect(parent.Spec.Strategy).To(BeEmpty())
+
+		By("rejecting to set the strategy of the parent")
+		parent.Spec.Strategy = samplev1.ApplyStrategySSA
+		err := k8sClient.Update(ctx, parent)
+		expectInvalid(err, "strategy is immutable")
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features declares the feature gates of the controller, set with
+// --feature-gates=Feature=true,...
+package features
+
+import (
+	"flag"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// ThreeWayMerge enables the ThreeWayMerge apply strategy, which patches
+	// children with a three-way JSON merge patch between the last applied
+	// child, the rendered child and the live child.
+	ThreeWayMerge featuregate.Feature = "ThreeWayMerge"
+
+	// RemoteClusters enables children in remote clusters referenced by
+	// kubeconfig Secrets.
+	RemoteClusters featuregate.Feature = "RemoteClusters"
+
+	// ProtectChildFields makes the child field protection webhook reject
+	// updates by other users to the child fields applied by the controller.
+	ProtectChildFields featuregate.Feature = "ProtectChildFields"
+)
+
+// defaultFeatures are the features of the controller with their stage and
+// default.
+var defaultFeatures = map[featuregate.Feature]featuregate.FeatureSpec{
+	ThreeWayMerge:      {Default: false, PreRelease: featuregate.Alpha},
+	RemoteClusters:     {Default: true, PreRelease: featuregate.Beta},
+	ProtectChildFields: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// defaultGate answers for components given no gate.
+var defaultGate = NewFeatureGate()
+
+var featureEnabled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "myresource_feature_enabled",
+	Help: "Whether a feature gate of the controller is enabled (1) or disabled (0), by name and stage.",
+}, []string{"name", "stage"})
+
+func init() {
+	metrics.Registry.MustRegister(featureEnabled)
+}
+
+// NewFeatureGate returns a gate knowing the features of the controller, all
+// set to their defaults.
+func NewFeatureGate() featuregate.MutableFeatureGate {
+	gate := featuregate.NewFeatureGate()
+	utilruntime.Must(gate.Add(defaultFeatures))
+	return gate
+}
+
+// Flag returns gate as the value of --feature-gates.
+func Flag(gate featuregate.MutableFeatureGate) flag.Value {
+	return &gateFlag{gate: gate}
+}
+
+type gateFlag struct {
+	gate featuregate.MutableFeatureGate
+}
+
+// String formats the state of the features of the controller as Feature=true,...
+func (f *gateFlag) String() string {
+	if f.gate == nil {
+		return ""
+	}
+	var values []string
+	for _, feature := range slices.Sorted(maps.Keys(defaultFeatures)) {
+		values = append(values, string(feature)+"="+strconv.FormatBool(f.gate.Enabled(feature)))
+	}
+	return strings.Join(values, ",")
+}
+
+// Set parses Feature=true,... into the gate.
+func (f *gateFlag) Set(value string) error {
+	return f.gate.Set(value)
+}
+
+// Enabled reports whether gate enables feature. A nil gate enables the
+// features that are enabled by default.
+func Enabled(gate featuregate.FeatureGate, feature featuregate.Feature) bool {
+	if gate == nil {
+		gate = defaultGate
+	}
+	return gate.Enabled(feature)
+}
+
+// RecordMetrics exposes whether gate enables each feature of the controller.
+func RecordMetrics(gate featuregate.FeatureGate) {
+	for feature, spec := range defaultFeatures {
+		stage := string(spec.PreRelease)
+		if spec.PreRelease == featuregate.GA {
+			stage = "GA"
+		}
+		value := 0.0
+		if Enabled(gate, feature) {
+			value = 1
+		}
+		featureEnabled.WithLabelValues(string(feature), stage).Set(value)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Feature gates", func() {
+	It("should enable beta features and disable alpha features by default", func() {
+		Expect(Enabled(nil, ThreeWayMerge)).To(BeFalse())
+		Expect(Enabled(nil, RemoteClusters)).To(BeTrue())
+		Expect(Enabled(nil, ProtectChildFields)).To(BeFalse())
+	})
+
+	It("should parse --feature-gates", func() {
+		gate := NewFeatureGate()
+		value := Flag(gate)
+		Expect(value.Set("ThreeWayMerge=true,RemoteClusters=false")).To(Succeed())
+		Expect(Enabled(gate, ThreeWayMerge)).To(BeTrue())
+		Expect(Enabled(gate, RemoteClusters)).To(BeFalse())
+		Expect(value.String()).To(Equal("ProtectChildFields=false,RemoteClusters=false,ThreeWayMerge=true"))
+
+		Expect(value.Set("Unknown=true")).To(MatchError(ContainSubstring("unrecognized feature gate")))
+	})
+
+	It("should expose the enabled features by name and stage", func() {
+		gate := NewFeatureGate()
+		Expect(gate.Set("ThreeWayMerge=true")).To(Succeed())
+		RecordMetrics(gate)
+		Expect(testutil.ToFloat64(featureEnabled.WithLabelValues("ThreeWayMerge", "ALPHA"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(featureEnabled.WithLabelValues("RemoteClusters", "BETA"))).To(Equal(1.0))
+
+		RecordMetrics(NewFeatureGate())
+		Expect(testutil.ToFloat64(featureEnabled.WithLabelValues("ThreeWayMerge", "ALPHA"))).To(Equal(0.0))
+	})
+})
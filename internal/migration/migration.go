@@ -0,0 +1,251 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration rewrites the stored objects of the custom resources of
+// the controller in the current storage version of their CRD, so that older
+// versions can be removed from the CRD once no object is stored in them.
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultPageSize is the number of objects listed, rewritten and recorded in
+// the progress at once.
+const DefaultPageSize = 100
+
+// CRDs are the names of the CRDs of the controller, in migration order.
+var CRDs = []string{
+	"myresources.sample.k8s-controller.ad",
+	"mychildresources.sample.k8s-controller.ad",
+}
+
+// Options configures a Migrator.
+type Options struct {
+	// Namespace and Name locate the ConfigMap recording the progress.
+	Namespace string
+	Name      string
+	// PageSize defaults to DefaultPageSize.
+	PageSize int64
+}
+
+// Progress is the migration state of a CRD, recorded as JSON in the progress
+// ConfigMap under the name of the CRD.
+type Progress struct {
+	// StorageVersion is the version the objects are rewritten in. The
+	// migration of a CRD starts over when its storage version changes.
+	StorageVersion string `json:"storageVersion"`
+	// Continue is the list continue token of the next page. It expires
+	// with the compaction of the API server storage, after which the
+	// migration starts over from the first page.
+	Continue string `json:"continue,omitempty"`
+	// Migrated is the number of objects rewritten so far.
+	Migrated int64 `json:"migrated"`
+	// Completed is set once every object has been rewritten and the stored
+	// versions of the CRD have been reduced to the storage version.
+	Completed bool `json:"completed,omitempty"`
+}
+
+// Migrator rewrites every object of a CRD in its storage version. A rewrite
+// is an update leaving the object unchanged: the API server encodes it in the
+// storage version. Progress is saved after every page, so that a Migrator
+// interrupted by a restart resumes where it stopped.
+type Migrator struct {
+	client client.Client
+	opts   Options
+}
+
+// NewMigrator returns a Migrator using c, which must read from the API server
+// rather than a cache and whose scheme must include the apiextensions and
+// core APIs.
+func NewMigrator(c client.Client, opts Options) *Migrator {
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+	return &Migrator{client: c, opts: opts}
+}
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get,resourceNames=myresources.sample.k8s-controller.ad;mychildresources.sample.k8s-controller.ad
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=update,resourceNames=myresources.sample.k8s-controller.ad;mychildresources.sample.k8s-controller.ad
+
+// Run migrates the CRDs named crds in order and returns their progress.
+func (m *Migrator) Run(ctx context.Context, crds ...string) (map[string]Progress, error) {
+	progress, err := m.loadProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range crds {
+		if err := m.migrate(ctx, name, progress); err != nil {
+			return progress.state, fmt.Errorf("migrating %s: %w", name, err)
+		}
+	}
+	return progress.state, nil
+}
+
+// migrate rewrites the objects of the CRD name, then drops the versions other
+// than the storage version from its stored versions.
+func (m *Migrator) migrate(ctx context.Context, name string, progress *progressRecord) error {
+	logger := log.FromContext(ctx).WithValues("crd", name)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := m.client.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+		return err
+	}
+	version := storageVersion(crd)
+	if version == "" {
+		return errors.New("no storage version")
+	}
+	state := progress.state[name]
+	if state.StorageVersion != version {
+		state = Progress{StorageVersion: version}
+	}
+	if state.Completed && slices.Equal(crd.Status.StoredVersions, []string{version}) {
+		logger.Info("Storage version migration already completed", "version", version, "migrated", state.Migrated)
+		return nil
+	}
+
+	gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: crd.Spec.Names.ListKind}
+	for !state.Completed {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		err := m.client.List(ctx, list, client.Limit(m.opts.PageSize), client.Continue(state.Continue))
+		if apierrors.IsResourceExpired(err) && state.Continue != "" {
+			logger.Info("Continue token expired, starting over from the first page")
+			state.Continue = ""
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			if err := m.rewrite(ctx, &list.Items[i]); err != nil {
+				return fmt.Errorf("rewriting %s: %w", client.ObjectKeyFromObject(&list.Items[i]), err)
+			}
+		}
+		state.Migrated += int64(len(list.Items))
+		state.Continue = list.GetContinue()
+		state.Completed = state.Continue == ""
+		if state.Completed {
+			if err := m.setStoredVersions(ctx, name, version); err != nil {
+				return err
+			}
+		}
+		if err := progress.save(ctx, m.client, name, state); err != nil {
+			return err
+		}
+		logger.V(1).Info("Migrated a page", "version", version, "migrated", state.Migrated)
+	}
+	logger.Info("Storage version migration completed", "version", version, "migrated", state.Migrated)
+	return nil
+}
+
+// rewrite updates obj unchanged. An object updated or deleted since it was
+// listed is already stored in the storage version, or no longer stored.
+func (m *Migrator) rewrite(ctx context.Context, obj *unstructured.Unstructured) error {
+	err := m.client.Update(ctx, obj)
+	if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// setStoredVersions records version as the only stored version of the CRD
+// name.
+func (m *Migrator) setStoredVersions(ctx context.Context, name, version string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := m.client.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+			return err
+		}
+		if storageVersion(crd) != version {
+			return fmt.Errorf("storage version changed from %s to %s during the migration", version, storageVersion(crd))
+		}
+		if slices.Equal(crd.Status.StoredVersions, []string{version}) {
+			return nil
+		}
+		crd.Status.StoredVersions = []string{version}
+		return m.client.Status().Update(ctx, crd)
+	})
+}
+
+// storageVersion returns the name of the storage version of crd.
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			return version.Name
+		}
+	}
+	return ""
+}
+
+// progressRecord is the progress ConfigMap and the progress it records.
+type progressRecord struct {
+	configMap *corev1.ConfigMap
+	state     map[string]Progress
+}
+
+// loadProgress reads the progress ConfigMap, creating it if missing.
+func (m *Migrator) loadProgress(ctx context.Context) (*progressRecord, error) {
+	cm := &corev1.ConfigMap{}
+	err := m.client.Get(ctx, client.ObjectKey{Namespace: m.opts.Namespace, Name: m.opts.Name}, cm)
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: m.opts.Namespace, Name: m.opts.Name}}
+		err = m.client.Create(ctx, cm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading the migration progress: %w", err)
+	}
+	record := &progressRecord{configMap: cm, state: map[string]Progress{}}
+	for name, data := range cm.Data {
+		var state Progress
+		if err := json.Unmarshal([]byte(data), &state); err != nil {
+			return nil, fmt.Errorf("invalid migration progress of %s: %w", name, err)
+		}
+		record.state[name] = state
+	}
+	return record, nil
+}
+
+// save records state as the progress of the CRD name.
+func (r *progressRecord) save(ctx context.Context, c client.Client, name string, state Progress) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if r.configMap.Data == nil {
+		r.configMap.Data = map[string]string{}
+	}
+	r.configMap.Data[name] = string(data)
+	if err := c.Update(ctx, r.configMap); err != nil {
+		return fmt.Errorf("saving the migration progress: %w", err)
+	}
+	r.state[name] = state
+	return nil
+}
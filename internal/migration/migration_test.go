@@ -0,0 +1,261 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	samplev2 "k8s-controller.ad/api/v2"
+)
+
+var _ = Describe("Migrator", func() {
+	const expired = "expired"
+
+	var (
+		ctx     context.Context
+		c       client.Client
+		objects []client.Object
+		pages   []string
+	)
+
+	crd := func(plural, kind string, versions ...string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: plural + "." + samplev1.GroupVersion.Group},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: samplev1.GroupVersion.Group,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: kind, ListKind: kind + "List"},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: versions},
+		}
+		for i, version := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{
+				Name:    version,
+				Served:  true,
+				Storage: i == len(versions)-1,
+			})
+		}
+		return crd
+	}
+
+	// paginate serves lists of custom resources in pages of the requested
+	// size, with the offset of the next page as continue token.
+	paginate := func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+		items, ok := list.(*unstructured.UnstructuredList)
+		if !ok {
+			return c.List(ctx, list, opts...)
+		}
+		listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+		if listOpts.Continue == expired {
+			return apierrors.NewResourceExpired("continue token expired")
+		}
+		pages = append(pages, items.GetKind()+"@"+listOpts.Continue)
+		if err := c.List(ctx, list); err != nil {
+			return err
+		}
+		slices.SortFunc(items.Items, func(a, b unstructured.Unstructured) int {
+			return strings.Compare(a.GetName(), b.GetName())
+		})
+		offset, _ := strconv.Atoi(listOpts.Continue)
+		end := min(offset+int(listOpts.Limit), len(items.Items))
+		if end < len(items.Items) {
+			items.SetContinue(strconv.Itoa(end))
+		}
+		items.Items = items.Items[offset:end]
+		return nil
+	}
+
+	build := func(initObjs ...client.Object) {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+		Expect(samplev1.AddToScheme(scheme)).To(Succeed())
+		Expect(samplev2.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(append(objects, initObjs...)...).
+			WithStatusSubresource(&apiextensionsv1.CustomResourceDefinition{}).
+			WithInterceptorFuncs(interceptor.Funcs{List: paginate}).
+			Build()
+	}
+
+	run := func() (map[string]Progress, error) {
+		return NewMigrator(c, Options{Namespace: "system", Name: "migration", PageSize: 2}).Run(ctx, CRDs...)
+	}
+
+	resourceVersions := func() map[string]string {
+		versions := map[string]string{}
+		parents := &samplev2.MyResourceList{}
+		Expect(c.List(ctx, parents)).To(Succeed())
+		for _, parent := range parents.Items {
+			versions[parent.Name] = parent.ResourceVersion
+		}
+		children := &samplev1.MyChildResourceList{}
+		Expect(c.List(ctx, children)).To(Succeed())
+		for _, child := range children.Items {
+			versions[child.Name] = child.ResourceVersion
+		}
+		return versions
+	}
+
+	storedVersions := func(name string) []string {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		Expect(c.Get(ctx, client.ObjectKey{Name: name}, crd)).To(Succeed())
+		return crd.Status.StoredVersions
+	}
+
+	progressOf := func(name string) Progress {
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "system", Name: "migration"}, cm)).To(Succeed())
+		var progress Progress
+		Expect(json.Unmarshal([]byte(cm.Data[name]), &progress)).To(Succeed())
+		return progress
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		pages = nil
+		objects = []client.Object{
+			crd("myresources", "MyResource", "v1", "v2"),
+			crd("mychildresources", "MyChildResource", "v1"),
+		}
+		for i := range 5 {
+			objects = append(objects, &samplev2.MyResource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("parent-%d", i)},
+			})
+		}
+		for i := range 3 {
+			objects = append(objects, &samplev1.MyChildResource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("child-%d", i)},
+			})
+		}
+	})
+
+	It("should rewrite every object page by page and reduce the stored versions", func() {
+		build()
+		before := resourceVersions()
+
+		progress, err := run()
+		Expect(err).NotTo(HaveOccurred())
+
+		after := resourceVersions()
+		for name, version := range before {
+			Expect(after[name]).NotTo(Equal(version), "%s was not rewritten", name)
+		}
+		Expect(pages).To(Equal([]string{
+			"MyResourceList@", "MyResourceList@2", "MyResourceList@4",
+			"MyChildResourceList@", "MyChildResourceList@2",
+		}))
+		Expect(progress).To(Equal(map[string]Progress{
+			CRDs[0]: {StorageVersion: "v2", Migrated: 5, Completed: true},
+			CRDs[1]: {StorageVersion: "v1", Migrated: 3, Completed: true},
+		}))
+		Expect(progressOf(CRDs[0])).To(Equal(progress[CRDs[0]]))
+		Expect(storedVersions(CRDs[0])).To(Equal([]string{"v2"}))
+		Expect(storedVersions(CRDs[1])).To(Equal([]string{"v1"}))
+	})
+
+	It("should resume from the recorded progress", func() {
+		build(progressConfigMap(map[string]Progress{
+			CRDs[0]: {StorageVersion: "v2", Continue: "4", Migrated: 4},
+			CRDs[1]: {StorageVersion: "v1", Migrated: 3, Completed: true},
+		}))
+		before := resourceVersions()
+
+		Expect(run()).Error().NotTo(HaveOccurred())
+
+		after := resourceVersions()
+		Expect(pages).To(Equal([]string{"MyResourceList@4"}))
+		Expect(after["parent-3"]).To(Equal(before["parent-3"]))
+		Expect(after["parent-4"]).NotTo(Equal(before["parent-4"]))
+		Expect(after["child-0"]).To(Equal(before["child-0"]))
+		Expect(progressOf(CRDs[0])).To(Equal(Progress{StorageVersion: "v2", Migrated: 5, Completed: true}))
+		Expect(storedVersions(CRDs[0])).To(Equal([]string{"v2"}))
+	})
+
+	It("should start over when the continue token expired", func() {
+		build(progressConfigMap(map[string]Progress{
+			CRDs[0]: {StorageVersion: "v2", Continue: expired, Migrated: 2},
+		}))
+
+		Expect(run()).Error().NotTo(HaveOccurred())
+
+		Expect(pages[:3]).To(Equal([]string{"MyResourceList@", "MyResourceList@2", "MyResourceList@4"}))
+		Expect(progressOf(CRDs[0]).Completed).To(BeTrue())
+	})
+
+	It("should start over when the storage version changed", func() {
+		build(progressConfigMap(map[string]Progress{
+			CRDs[0]: {StorageVersion: "v1", Migrated: 5, Completed: true},
+		}))
+
+		Expect(run()).Error().NotTo(HaveOccurred())
+
+		Expect(pages[:3]).To(Equal([]string{"MyResourceList@", "MyResourceList@2", "MyResourceList@4"}))
+		Expect(progressOf(CRDs[0])).To(Equal(Progress{StorageVersion: "v2", Migrated: 5, Completed: true}))
+	})
+
+	It("should keep the progress of the pages migrated before a failure", func() {
+		build()
+		c = interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if obj.GetName() == "parent-3" {
+					return apierrors.NewBadRequest("denied")
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		})
+
+		_, err := run()
+		Expect(err).To(MatchError(ContainSubstring("rewriting default/parent-3")))
+
+		Expect(progressOf(CRDs[0])).To(Equal(Progress{StorageVersion: "v2", Continue: "2", Migrated: 2}))
+		Expect(storedVersions(CRDs[0])).To(Equal([]string{"v1", "v2"}))
+	})
+})
+
+// progressConfigMap returns the progress ConfigMap recording progress.
+func progressConfigMap(progress map[string]Progress) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "system", Name: "migration"},
+		Data:       map[string]string{},
+	}
+	for name, state := range progress {
+		data, err := json.Marshal(state)
+		Expect(err).NotTo(HaveOccurred())
+		cm.Data[name] = string(data)
+	}
+	return cm
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+// Health of a child as reported by the status subcommand.
+const (
+	// HealthHealthy children are applied and controlled by their parent.
+	HealthHealthy = "Healthy"
+	// HealthPending children are desired but not applied by a reconcile yet.
+	HealthPending = "Pending"
+	// HealthMissing children were applied but no longer exist.
+	HealthMissing = "Missing"
+	// HealthConflict children exist but are controlled by another parent.
+	HealthConflict = "Conflict"
+	// HealthDeleting children are being deleted.
+	HealthDeleting = "Deleting"
+	// HealthPruning children are no longer desired and are pruned by the next
+	// reconcile.
+	HealthPruning = "Pruning"
+	// HealthRemote children are in a remote cluster the plugin does not read.
+	HealthRemote = "Remote"
+)
+
+// child is a child of a parent, desired by the parent, recorded in its
+// inventory or both.
+type child struct {
+	cluster, namespace, name string
+	// desired is the rendered child, nil when the child is no longer desired.
+	desired *samplev1.MyChildResource
+	// inventory reports whether the child is in the inventory of the parent.
+	inventory bool
+	// live is the child in the cluster, nil when it does not exist or is in
+	// a remote cluster.
+	live *samplev1.MyChildResource
+}
+
+func (c *child) String() string {
+	key := c.namespace + "/" + c.name
+	if c.cluster != "" {
+		key = c.cluster + ":" + key
+	}
+	return key
+}
+
+// health returns the health of c, a child of parent.
+func (c *child) health(parent *samplev1.MyResource) string {
+	switch {
+	case c.cluster != "":
+		return HealthRemote
+	case c.live == nil && c.inventory:
+		return HealthMissing
+	case c.live == nil:
+		return HealthPending
+	case c.live.DeletionTimestamp != nil:
+		return HealthDeleting
+	case c.live.Labels[controller.LabelParentUID] != string(parent.UID):
+		return HealthConflict
+	case c.desired == nil:
+		return HealthPruning
+	case !c.inventory:
+		return HealthPending
+	default:
+		return HealthHealthy
+	}
+}
+
+// childrenOf returns the desired and inventoried children of parent, sorted
+// by cluster, namespace and name, with the live children of the local
+// cluster read through c. The children setting no strategy are rendered with
+// defaultStrategy, the default strategy of the manager.
+func childrenOf(
+	ctx context.Context, c client.Reader, parent *samplev1.MyResource, defaultStrategy samplev1.ApplyStrategy,
+) ([]*child, error) {
+	desired, err := controller.DesiredChildren(ctx, c, parent, defaultStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("rendering the children of %s/%s: %w", parent.Namespace, parent.Name, err)
+	}
+
+	byKey := map[string]*child{}
+	var children []*child
+	add := func(cluster, namespace, name string) *child {
+		ch := &child{cluster: cluster, namespace: namespace, name: name}
+		if existing, ok := byKey[ch.String()]; ok {
+			return existing
+		}
+		byKey[ch.String()] = ch
+		children = append(children, ch)
+		return ch
+	}
+	for _, rendered := range desired {
+		add(rendered.Annotations[controller.AnnotationCluster], rendered.Namespace, rendered.Name).desired = rendered
+	}
+	for _, ref := range parent.Status.Inventory {
+		add(ref.Cluster, ref.Namespace, ref.Name).inventory = true
+	}
+
+	for _, ch := range children {
+		if ch.cluster != "" {
+			continue
+		}
+		live := &samplev1.MyChildResource{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: ch.namespace, Name: ch.name}, live)
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return nil, err
+		default:
+			ch.live = live
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].cluster != children[j].cluster {
+			return children[i].cluster < children[j].cluster
+		}
+		if children[i].namespace != children[j].namespace {
+			return children[i].namespace < children[j].namespace
+		}
+		return children[i].name < children[j].name
+	})
+	return children, nil
+}
+
+// getParent reads the MyResource name in namespace.
+func getParent(ctx context.Context, c client.Reader, namespace, name string) (*samplev1.MyResource, error) {
+	parent := &samplev1.MyResource{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, parent); err != nil {
+		return nil, err
+	}
+	return parent, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+func newDiffCommand(env *environment) *cobra.Command {
+	var defaultStrategy string
+	cmd := &cobra.Command{
+		Use:   "diff NAME",
+		Short: "Show the differences between the desired and the live children of a MyResource",
+		Long: "diff compares the children a reconcile of the MyResource would apply with the children in the\n" +
+			"cluster, limited to the labels and annotations set by the child templates. Children in remote\n" +
+			"clusters are skipped. Like kubectl diff, it exits with 1 when children differ and 2 on errors.\n" +
+			"Children whose MyResource and template set no strategy are applied with the default strategy\n" +
+			"of the manager: pass its --default-strategy when it is not Suggested.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(samplev1.ApplyStrategies, samplev1.ApplyStrategy(defaultStrategy)) {
+				return &ExitError{Code: 2, Err: fmt.Errorf("unsupported default strategy %q", defaultStrategy)}
+			}
+			c, namespace, err := env.client()
+			if err != nil {
+				return &ExitError{Code: 2, Err: err}
+			}
+			different, err := printDiff(cmd.Context(), c, env.out, namespace, args[0], samplev1.ApplyStrategy(defaultStrategy))
+			if err != nil {
+				return &ExitError{Code: 2, Err: err}
+			}
+			if different {
+				return &ExitError{Code: 1}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&defaultStrategy, "default-strategy", string(samplev1.ApplyStrategySuggested),
+		"The apply strategy of children whose MyResource and template set none, as given to the manager.")
+	return cmd
+}
+
+// childView is the part of a child set by its template, which diff compares.
+type childView struct {
+	Metadata childViewMetadata            `json:"metadata"`
+	Spec     samplev1.MyChildResourceSpec `json:"spec"`
+}
+
+type childViewMetadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// printDiff writes a unified diff between the live and the desired children
+// of the MyResource name in namespace, rendered with defaultStrategy, and
+// reports whether any child differs.
+func printDiff(
+	ctx context.Context, c client.Reader, out io.Writer, namespace, name string, defaultStrategy samplev1.ApplyStrategy,
+) (bool, error) {
+	parent, err := getParent(ctx, c, namespace, name)
+	if err != nil {
+		return false, err
+	}
+	children, err := childrenOf(ctx, c, parent, defaultStrategy)
+	if err != nil {
+		return false, err
+	}
+
+	different := false
+	for _, ch := range children {
+		if ch.cluster != "" {
+			fmt.Fprintf(out, "# %s skipped, remote clusters are not compared\n", ch)
+			continue
+		}
+		live, err := ch.view(ch.live)
+		if err != nil {
+			return false, err
+		}
+		desired, err := ch.view(ch.desired)
+		if err != nil {
+			return false, err
+		}
+		if live == desired {
+			continue
+		}
+		different = true
+		if err := difflib.WriteUnifiedDiff(out, difflib.UnifiedDiff{
+			A:        lines(live),
+			B:        lines(desired),
+			FromFile: "live/" + ch.String(),
+			ToFile:   "desired/" + ch.String(),
+			Context:  3,
+		}); err != nil {
+			return false, err
+		}
+	}
+	return different, nil
+}
+
+// view returns obj, the live or desired version of c, as the YAML of its
+// childView. Labels and annotations not set by the template are left out,
+// unless c is no longer desired. A nil obj is empty.
+func (c *child) view(obj *samplev1.MyChildResource) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	view := childView{
+		Metadata: childViewMetadata{
+			Name:        obj.Name,
+			Namespace:   obj.Namespace,
+			Labels:      obj.Labels,
+			Annotations: obj.Annotations,
+		},
+		Spec: obj.Spec,
+	}
+	if c.desired != nil && obj != c.desired {
+		view.Metadata.Labels = selectKeys(obj.Labels, c.desired.Labels)
+		view.Metadata.Annotations = selectKeys(obj.Annotations, c.desired.Annotations)
+	}
+	data, err := yaml.Marshal(view)
+	return string(data), err
+}
+
+// lines splits text into its lines, keeping their line breaks.
+func lines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// selectKeys returns the entries of values whose key is in keys.
+func selectKeys(values, keys map[string]string) map[string]string {
+	selected := maps.Clone(values)
+	maps.DeleteFunc(selected, func(key, _ string) bool {
+		_, ok := keys[key]
+		return !ok
+	})
+	return selected
+}
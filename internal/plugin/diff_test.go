@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+var _ = Describe("diff", func() {
+	var (
+		parent *samplev1.MyResource
+		a      *samplev1.MyChildResource
+	)
+
+	BeforeEach(func() {
+		parent = testParent()
+		a = testChild("default", "a", "parent-uid")
+		a.Labels["team"] = "a"
+		a.Spec.Foo = "desired"
+	})
+
+	It("should report no difference when the live children match", func() {
+		parent.Spec.Children = parent.Spec.Children[:1]
+		parent.Status.Inventory = parent.Status.Inventory[:1]
+		c := newTestClient(parent, a)
+
+		out := &strings.Builder{}
+		Expect(printDiff(context.Background(), c, out, "default", "parent", "")).To(BeFalse())
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("should render the children setting no strategy with the default strategy", func() {
+		parent.Spec.Strategy = ""
+		parent.Spec.Children = parent.Spec.Children[:1]
+		parent.Status.Inventory = parent.Status.Inventory[:1]
+		a.Annotations[controller.AnnotationStrategy] = string(samplev1.ApplyStrategyPatch)
+		c := newTestClient(parent, a)
+
+		out := &strings.Builder{}
+		Expect(printDiff(context.Background(), c, out, "default", "parent", samplev1.ApplyStrategyPatch)).To(BeFalse())
+		Expect(out.String()).To(BeEmpty())
+
+		Expect(printDiff(context.Background(), c, out, "default", "parent", "")).To(BeTrue())
+		Expect(out.String()).To(ContainSubstring("-    sample.k8s-controller.ad/apply-strategy: Patch\n" +
+			"+    sample.k8s-controller.ad/apply-strategy: Suggested\n"))
+	})
+
+	It("should keep the strategy set by the MyResource whatever the default strategy", func() {
+		parent.Spec.Children = parent.Spec.Children[:1]
+		parent.Status.Inventory = parent.Status.Inventory[:1]
+		c := newTestClient(parent, a)
+
+		out := &strings.Builder{}
+		Expect(printDiff(context.Background(), c, out, "default", "parent", samplev1.ApplyStrategyPatch)).To(BeFalse())
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("should diff the template fields of changed, missing and pruned children", func() {
+		a.Labels["team"] = "b"
+		a.Spec.Foo = "live"
+		c := newTestClient(parent, a, testChild("default", "old", "parent-uid"))
+
+		out := &strings.Builder{}
+		Expect(printDiff(context.Background(), c, out, "default", "parent", "")).To(BeTrue())
+		Expect(out.String()).To(ContainSubstring(`--- live/default/a
++++ desired/default/a
+@@ -2,9 +2,9 @@
+   annotations:
+     sample.k8s-controller.ad/apply-strategy: SSA
+   labels:
+-    team: b
++    team: a
+   name: a
+   namespace: default
+ spec:
+-  foo: live
++  foo: desired
+   fooValueDefault: ho-ho-ho
+`))
+		Expect(out.String()).To(ContainSubstring("--- live/default/old\n+++ desired/default/old\n@@ -1,11 +0,0 @@\n"))
+		Expect(out.String()).To(ContainSubstring("--- live/shared/b\n+++ desired/shared/b\n@@ -0,0 +1,7 @@\n"))
+		Expect(out.String()).To(ContainSubstring("# east:default/c skipped, remote clusters are not compared\n"))
+	})
+
+	It("should exit with 1 on differences and 2 on errors", func() {
+		Expect(ExitCode(nil)).To(Equal(0))
+		Expect(ExitCode(&ExitError{Code: 1})).To(Equal(1))
+		Expect(ExitCode(&ExitError{Code: 2, Err: context.Canceled})).To(Equal(2))
+		Expect(ExitCode(context.Canceled)).To(Equal(1))
+	})
+})
@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	"k8s-controller.ad/internal/controller"
+)
+
+func newOwnersCommand(env *environment) *cobra.Command {
+	return &cobra.Command{
+		Use:   "owners NAME",
+		Short: "Show the field managers owning every field of a MyResource and its children",
+		Long: "owners lists the field paths of a MyResource and of its children in the local cluster with the\n" +
+			"field managers owning them, read from managedFields. The controller applies children as " +
+			controller.ManagerName + ".",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, namespace, err := env.client()
+			if err != nil {
+				return err
+			}
+			return printOwners(cmd.Context(), c, env.out, namespace, args[0])
+		},
+	}
+}
+
+// printOwners writes the owners of the fields of the MyResource name in
+// namespace and of its live children.
+func printOwners(ctx context.Context, c client.Reader, out io.Writer, namespace, name string) error {
+	parent, err := getParent(ctx, c, namespace, name)
+	if err != nil {
+		return err
+	}
+	children, err := childrenOf(ctx, c, parent, "")
+	if err != nil {
+		return err
+	}
+
+	if err := printFieldOwners(out, "MyResource", parent); err != nil {
+		return err
+	}
+	for _, ch := range children {
+		if ch.live == nil {
+			continue
+		}
+		fmt.Fprintln(out)
+		if err := printFieldOwners(out, "MyChildResource", ch.live); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printFieldOwners writes the field paths of obj, a kind, with their managers.
+func printFieldOwners(out io.Writer, kind string, obj client.Object) error {
+	owners, err := fieldOwners(obj.GetManagedFields())
+	if err != nil {
+		return fmt.Errorf("%s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+	paths := make([]string, 0, len(owners))
+	for path := range owners {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "%s %s/%s\n", kind, obj.GetNamespace(), obj.GetName())
+	fmt.Fprintln(w, "FIELD\tMANAGERS")
+	for _, path := range paths {
+		fmt.Fprintf(w, "%s\t%s\n", path, strings.Join(owners[path], ", "))
+	}
+	return w.Flush()
+}
+
+// fieldOwners returns the managers of every field path in entries, described
+// with their operation and subresource.
+func fieldOwners(entries []metav1.ManagedFieldsEntry) (map[string][]string, error) {
+	owners := map[string][]string{}
+	for _, entry := range entries {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("fields of manager %s: %w", entry.Manager, err)
+		}
+		operation := string(entry.Operation)
+		if entry.Subresource != "" {
+			operation += " " + entry.Subresource
+		}
+		if entry.Time != nil {
+			operation += " " + entry.Time.UTC().Format(time.RFC3339)
+		}
+		manager := fmt.Sprintf("%s (%s)", entry.Manager, operation)
+		fields.Iterate(func(path fieldpath.Path) {
+			owners[path.String()] = append(owners[path.String()], manager)
+		})
+	}
+	return owners, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s-controller.ad/internal/controller"
+)
+
+var _ = Describe("owners", func() {
+	It("should list the managers of every field path of the parent and its live children", func() {
+		applied := metav1.NewTime(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+		parent := testParent()
+		parent.Spec.Children = parent.Spec.Children[:1]
+		parent.Status.Inventory = nil
+		parent.ManagedFields = []metav1.ManagedFieldsEntry{{
+			Manager:    "kubectl",
+			Operation:  metav1.ManagedFieldsOperationApply,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:children":{},"f:strategy":{}}}`)},
+		}}
+		a := testChild("default", "a", "parent-uid")
+		a.ManagedFields = []metav1.ManagedFieldsEntry{
+			{
+				Manager:    controller.ManagerName,
+				Operation:  metav1.ManagedFieldsOperationApply,
+				Time:       &applied,
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:team":{}}},"f:spec":{"f:foo":{}}}`)},
+			},
+			{
+				Manager:     "operator",
+				Operation:   metav1.ManagedFieldsOperationUpdate,
+				Subresource: "status",
+				FieldsType:  "FieldsV1",
+				FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:foo":{}},"f:status":{"f:state":{}}}`)},
+			},
+		}
+		c := newTestClient(parent, a)
+
+		out := &strings.Builder{}
+		Expect(printOwners(context.Background(), c, out, "default", "parent")).To(Succeed())
+		Expect(out.String()).To(Equal(`MyResource default/parent
+FIELD           MANAGERS
+.spec.children  kubectl (Apply)
+.spec.strategy  kubectl (Apply)
+
+MyChildResource default/a
+FIELD                  MANAGERS
+.metadata.labels.team  ssa-manager (Apply 2025-01-02T03:04:05Z)
+.spec.foo              ssa-manager (Apply 2025-01-02T03:04:05Z), operator (Update status)
+.status.state          operator (Update status)
+`))
+	})
+
+	It("should fail on invalid managed fields", func() {
+		_, err := fieldOwners([]metav1.ManagedFieldsEntry{{
+			Manager:  "broken",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`[]`)},
+		}})
+		Expect(err).To(MatchError(ContainSubstring("fields of manager broken")))
+	})
+})
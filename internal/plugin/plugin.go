@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin implements kubectl-myresource, the kubectl plugin inspecting
+// MyResources and the children the controller applies for them.
+package plugin
+
+import (
+	"errors"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(samplev1.AddToScheme(scheme))
+}
+
+// ExitError ends the plugin with Code. Its message is not printed when Err
+// is nil.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return ""
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the plugin for the error returned by its
+// command: the code of an ExitError, 1 for other errors.
+func ExitCode(err error) int {
+	var exitErr *ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	default:
+		return 1
+	}
+}
+
+// environment connects the subcommands to the cluster selected by the
+// kubeconfig flags.
+type environment struct {
+	out          io.Writer
+	clientConfig clientcmd.ClientConfig
+}
+
+// client returns a client of the cluster and the namespace selected by the
+// kubeconfig flags.
+func (e *environment) client() (client.Client, string, error) {
+	namespace, _, err := e.clientConfig.Namespace()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := e.clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	return c, namespace, err
+}
+
+// NewCommand returns the root command of kubectl-myresource, writing its
+// output to out.
+func NewCommand(out io.Writer) *cobra.Command {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{}
+	env := &environment{
+		out:          out,
+		clientConfig: clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "kubectl-myresource",
+		Short: "Inspect MyResources and their children",
+		Long: "kubectl-myresource shows the state of a MyResource and of the children the controller\n" +
+			"applies for it, and requests reconciles.",
+		// Usage is shown as run through kubectl.
+		Annotations:   map[string]string{cobra.CommandDisplayNameAnnotation: "kubectl myresource"},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.SetOut(out)
+	cmd.PersistentFlags().StringVar(&loadingRules.ExplicitPath, clientcmd.RecommendedConfigPathFlag, "",
+		"Path to the kubeconfig file to use.")
+	clientcmd.BindOverrideFlags(overrides, cmd.PersistentFlags(), clientcmd.RecommendedConfigOverrideFlags(""))
+
+	cmd.AddCommand(
+		newStatusCommand(env),
+		newDiffCommand(env),
+		newOwnersCommand(env),
+		newReconcileCommand(env),
+	)
+	return cmd
+}
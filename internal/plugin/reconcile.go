@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"k8s-controller.ad/internal/controller"
+)
+
+func newReconcileCommand(env *environment) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reconcile NAME",
+		Short: "Request a reconcile of a MyResource now",
+		Long: "reconcile sets the " + controller.AnnotationReconcileRequestedAt + " annotation of the\n" +
+			"MyResource to the current time, which triggers a reconcile whatever the event filters of the controller.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, namespace, err := env.client()
+			if err != nil {
+				return err
+			}
+			return requestReconcile(cmd.Context(), c, env.out, namespace, args[0], time.Now())
+		},
+	}
+}
+
+// requestReconcile sets AnnotationReconcileRequestedAt of the MyResource name
+// in namespace to now.
+func requestReconcile(ctx context.Context, c client.Client, out io.Writer, namespace, name string, now time.Time) error {
+	parent, err := getParent(ctx, c, namespace, name)
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFrom(parent.DeepCopy())
+	if parent.Annotations == nil {
+		parent.Annotations = map[string]string{}
+	}
+	parent.Annotations[controller.AnnotationReconcileRequestedAt] = now.UTC().Format(time.RFC3339Nano)
+	if err := c.Patch(ctx, parent, patch); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "myresource %s/%s reconcile requested\n", parent.Namespace, parent.Name)
+	return err
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+var _ = Describe("reconcile", func() {
+	It("should set the reconcile request annotation to the current time", func() {
+		ctx := context.Background()
+		c := newTestClient(testParent())
+		now := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+
+		out := &strings.Builder{}
+		Expect(requestReconcile(ctx, c, out, "default", "parent", now)).To(Succeed())
+		Expect(out.String()).To(Equal("myresource default/parent reconcile requested\n"))
+
+		parent := &samplev1.MyResource{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "parent"}, parent)).To(Succeed())
+		Expect(parent.Annotations).To(HaveKeyWithValue(controller.AnnotationReconcileRequestedAt,
+			"2025-01-02T03:04:05.000000006Z"))
+	})
+})
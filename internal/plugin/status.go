@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+func newStatusCommand(env *environment) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status NAME",
+		Short: "Show a MyResource, its conditions and the health of its children as a tree",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, namespace, err := env.client()
+			if err != nil {
+				return err
+			}
+			return printStatus(cmd.Context(), c, env.out, namespace, args[0])
+		},
+	}
+}
+
+// printStatus writes the tree of the MyResource name in namespace: its
+// revision and conditions, the sync state of its remote clusters and the
+// health of its children.
+func printStatus(ctx context.Context, c client.Reader, out io.Writer, namespace, name string) error {
+	parent, err := getParent(ctx, c, namespace, name)
+	if err != nil {
+		return err
+	}
+	children, err := childrenOf(ctx, c, parent, "")
+	if err != nil {
+		return err
+	}
+
+	table := &strings.Builder{}
+	w := tabwriter.NewWriter(table, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "MyResource %s/%s\n", parent.Namespace, parent.Name)
+	fmt.Fprintf(w, "│ Revision %d\tgeneration %d, observed %d\n",
+		parent.Status.Revision, parent.Generation, parent.Status.ObservedGeneration)
+	for _, condition := range parent.Status.Conditions {
+		fmt.Fprintf(w, "│ %s=%s\t%s: %s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+	}
+	for _, cluster := range parent.Status.Clusters {
+		fmt.Fprintf(w, "│ Cluster %s\t%s\n", cluster.Name, clusterState(cluster))
+	}
+	for i, ch := range children {
+		branch := "├──"
+		if i == len(children)-1 {
+			branch = "└──"
+		}
+		health := ch.health(parent)
+		fmt.Fprintf(w, "%s MyChildResource %s\t%s\t%s\n", branch, ch, health, ch.details(parent, health))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	// Children without details leave the padding of their health behind.
+	for _, line := range strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n") {
+		if _, err := fmt.Fprintln(out, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// details explains the health of c, a child of parent.
+func (c *child) details(parent *samplev1.MyResource, health string) string {
+	switch health {
+	case HealthRemote:
+		for _, cluster := range parent.Status.Clusters {
+			if cluster.Name == c.cluster {
+				return clusterState(cluster)
+			}
+		}
+		return "cluster not synced yet"
+	case HealthConflict:
+		return fmt.Sprintf("controlled by %s/%s",
+			c.live.Labels[controller.LabelParentNamespace], c.live.Labels[controller.LabelParentName])
+	}
+	if c.live != nil && c.live.Status.State != "" {
+		return "state " + c.live.Status.State
+	}
+	return ""
+}
+
+// clusterState describes the sync state of a remote cluster.
+func clusterState(cluster samplev1.ClusterStatus) string {
+	if !cluster.Synced {
+		return "not synced: " + cluster.Message
+	}
+	return fmt.Sprintf("synced, %d children", cluster.Children)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"k8s-controller.ad/internal/controller"
+)
+
+var _ = Describe("status", func() {
+	It("should show the parent, its conditions and the health of its children as a tree", func() {
+		a := testChild("default", "a", "parent-uid")
+		a.Status.State = "Running"
+		old := testChild("default", "old", "parent-uid")
+		b := testChild("shared", "b", "other-uid")
+		b.Labels[controller.LabelParentName] = "other"
+		c := newTestClient(testParent(), a, old, b)
+
+		out := &strings.Builder{}
+		Expect(printStatus(context.Background(), c, out, "default", "parent")).To(Succeed())
+		Expect(out.String()).To(Equal(`MyResource default/parent
+│ Revision 2                        generation 3, observed 2
+│ Ready=True                        Applied: 3 children applied from revision 2
+│ Cluster east                      synced, 1 children
+├── MyChildResource default/a       Healthy   state Running
+├── MyChildResource default/old     Pruning
+├── MyChildResource shared/b        Conflict  controlled by default/other
+└── MyChildResource east:default/c  Remote    synced, 1 children
+`))
+	})
+
+	It("should report children not applied yet", func() {
+		parent := testParent()
+		parent.Status.Inventory = nil
+		c := newTestClient(parent)
+
+		out := &strings.Builder{}
+		Expect(printStatus(context.Background(), c, out, "default", "parent")).To(Succeed())
+		Expect(out.String()).To(MatchRegexp(`MyChildResource default/a +Pending\n`))
+	})
+
+	It("should fail for a missing parent", func() {
+		err := printStatus(context.Background(), newTestClient(), &strings.Builder{}, "default", "parent")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
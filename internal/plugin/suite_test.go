@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+func TestPlugin(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Plugin Suite")
+}
+
+// testParent returns a parent with a local, a shared-namespace and a remote
+// child template, whose inventory also holds a child no longer desired.
+func testParent() *samplev1.MyResource {
+	return &samplev1.MyResource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "parent", UID: "parent-uid", Generation: 3},
+		Spec: samplev1.MyResourceSpec{
+			Strategy: samplev1.ApplyStrategySSA,
+			Children: []samplev1.ChildTemplate{
+				{Name: "a", Labels: map[string]string{"team": "a"}, Spec: samplev1.MyChildResourceSpec{Foo: "desired"}},
+				{Name: "b", Namespace: "shared"},
+				{Name: "c", Cluster: "east"},
+			},
+		},
+		Status: samplev1.MyResourceStatus{
+			ObservedGeneration: 2,
+			Revision:           2,
+			Inventory: []samplev1.ChildReference{
+				{Namespace: "default", Name: "a"},
+				{Namespace: "default", Name: "old"},
+				{Namespace: "shared", Name: "b"},
+				{Cluster: "east", Namespace: "default", Name: "c"},
+			},
+			Clusters: []samplev1.ClusterStatus{{Name: "east", Synced: true, Children: 1}},
+			Conditions: []metav1.Condition{{
+				Type:    samplev1.ConditionReady,
+				Status:  metav1.ConditionTrue,
+				Reason:  "Applied",
+				Message: "3 children applied from revision 2",
+			}},
+		},
+	}
+}
+
+// testChild returns a live child controlled by the parent with parentUID.
+func testChild(namespace, name, parentUID string) *samplev1.MyChildResource {
+	child := &samplev1.MyChildResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels: map[string]string{
+				controller.LabelParentNamespace: "default",
+				controller.LabelParentName:      "parent",
+				controller.LabelParentUID:       parentUID,
+			},
+			Annotations: map[string]string{controller.AnnotationStrategy: string(samplev1.ApplyStrategySSA)},
+		},
+	}
+	child.Spec.SetDefaults()
+	return child
+}
+
+// newTestClient returns a fake client holding objs.
+func newTestClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
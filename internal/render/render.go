@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render renders the children of MyResource manifests without a
+// cluster, through the validation and templating of the controller, so that
+// they can be previewed in CI.
+package render
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	samplev2 "k8s-controller.ad/api/v2"
+	"k8s-controller.ad/internal/controller"
+	webhooksamplev1 "k8s-controller.ad/internal/webhook/v1"
+)
+
+// Output formats of Write.
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+var (
+	scheme  = runtime.NewScheme()
+	decoder runtime.Decoder
+)
+
+func init() {
+	utilruntime.Must(samplev1.AddToScheme(scheme))
+	utilruntime.Must(samplev2.AddToScheme(scheme))
+	decoder = serializer.NewCodecFactory(scheme).UniversalDeserializer()
+}
+
+// Options configures the rendering.
+type Options struct {
+	// Namespace is the namespace of MyResources that set none.
+	Namespace string
+	// DefaultStrategy applies the children whose parent and template set no
+	// strategy, as the default strategy of the manager does: the API server
+	// leaves spec.strategy empty when it is not set.
+	// ApplyStrategySuggested when empty.
+	DefaultStrategy samplev1.ApplyStrategy
+	// Webhook configures the validation of the MyResources, as the
+	// validating webhook of the controller does.
+	Webhook webhooksamplev1.Options
+}
+
+// Decode reads the MyResources of the multi-document YAML or JSON stream r,
+// converted to v1. Documents of other kinds are skipped.
+func Decode(r io.Reader) ([]*samplev1.MyResource, error) {
+	var parents []*samplev1.MyResource
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return parents, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var meta runtime.TypeMeta
+		if err := yaml.Unmarshal(doc, &meta); err != nil {
+			return nil, err
+		}
+		if meta.Kind != "MyResource" || meta.GroupVersionKind().Group != samplev1.GroupVersion.Group {
+			continue
+		}
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		switch parent := obj.(type) {
+		case *samplev1.MyResource:
+			parents = append(parents, parent)
+		case *samplev2.MyResource:
+			converted := &samplev1.MyResource{}
+			if err := converted.ConvertFrom(parent); err != nil {
+				return nil, err
+			}
+			parents = append(parents, converted)
+		}
+	}
+}
+
+// Render validates parent like the validating webhook and returns its
+// children rendered like the reconciler renders them. The metadata tracking
+// the parent is added when the children are applied and is not rendered.
+func Render(ctx context.Context, parent *samplev1.MyResource, opts Options) ([]*samplev1.MyChildResource, error) {
+	if parent.Namespace == "" {
+		parent.Namespace = opts.Namespace
+	}
+	if parent.Spec.RollbackTo != nil {
+		return nil, errors.New("spec.rollbackTo applies a revision recorded in the cluster and cannot be rendered offline")
+	}
+	validator := &webhooksamplev1.MyResourceCustomValidator{Options: opts.Webhook}
+	if _, err := validator.ValidateCreate(ctx, parent); err != nil {
+		return nil, err
+	}
+	children, err := controller.RenderChildren(parent, opts.DefaultStrategy)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		child.APIVersion = samplev1.GroupVersion.String()
+		child.Kind = "MyChildResource"
+	}
+	return children, nil
+}
+
+// Write writes children to out in format: YAML documents, or a JSON List
+// like kubectl get -o json.
+func Write(out io.Writer, children []*samplev1.MyChildResource, format string) error {
+	switch format {
+	case FormatYAML:
+		for i, child := range children {
+			data, err := yaml.Marshal(child)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				if _, err := io.WriteString(out, "---\n"); err != nil {
+					return err
+				}
+			}
+			if _, err := out.Write(data); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatJSON:
+		list := struct {
+			APIVersion string                      `json:"apiVersion"`
+			Kind       string                      `json:"kind"`
+			Items      []*samplev1.MyChildResource `json:"items"`
+		}{APIVersion: "v1", Kind: "List", Items: children}
+		if list.Items == nil {
+			list.Items = []*samplev1.MyChildResource{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(list)
+	default:
+		return fmt.Errorf("unsupported output format %q, expected %s or %s", format, FormatYAML, FormatJSON)
+	}
+}
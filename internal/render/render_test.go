@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+const manifests = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+---
+apiVersion: sample.k8s-controller.ad/v1
+kind: MyResource
+metadata:
+  name: from-v1
+  namespace: team
+spec:
+  strategy: SSA
+  children:
+  - name: b
+  - name: a
+    labels:
+      tier: web
+    spec:
+      foo: bar
+---
+apiVersion: sample.k8s-controller.ad/v2
+kind: MyResource
+metadata:
+  name: from-v2
+spec:
+  strategy:
+    type: Patch
+  children:
+  - metadata:
+      name: c
+    strategy:
+      type: Replace
+`
+
+var _ = Describe("Render", func() {
+	var (
+		ctx  context.Context
+		opts Options
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		opts = Options{Namespace: "default"}
+	})
+
+	It("should decode v1 and v2 MyResources and skip other kinds", func() {
+		parents, err := Decode(strings.NewReader(manifests))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parents).To(HaveLen(2))
+		Expect(parents[0].Name).To(Equal("from-v1"))
+		Expect(parents[1].Name).To(Equal("from-v2"))
+		Expect(parents[1].Spec.Strategy).To(Equal(samplev1.ApplyStrategyPatch))
+		Expect(parents[1].Spec.Children[0].Name).To(Equal("c"))
+		Expect(parents[1].Spec.Children[0].Strategy).To(Equal(samplev1.ApplyStrategyReplace))
+	})
+
+	It("should fail on malformed documents", func() {
+		_, err := Decode(strings.NewReader("apiVersion: sample.k8s-controller.ad/v1\nkind: MyResource\nspec: [\n"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should render the children like the reconciler", func() {
+		parents, err := Decode(strings.NewReader(manifests))
+		Expect(err).NotTo(HaveOccurred())
+
+		children, err := Render(ctx, parents[0], opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(children).To(HaveLen(2))
+		Expect(children[0].Name).To(Equal("a"))
+		Expect(children[0].Namespace).To(Equal("team"))
+		Expect(children[0].Kind).To(Equal("MyChildResource"))
+		Expect(children[0].Labels).To(Equal(map[string]string{"tier": "web"}))
+		Expect(children[0].Annotations).To(HaveKeyWithValue(controller.AnnotationStrategy, "SSA"))
+		Expect(children[0].Spec.FooValueDefault).To(Equal(samplev1.DefaultFooValue))
+
+		children, err = Render(ctx, parents[1], opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(children[0].Namespace).To(Equal("default"))
+		Expect(children[0].Annotations).To(HaveKeyWithValue(controller.AnnotationStrategy, "Replace"))
+	})
+
+	It("should apply the default strategy of the manager to MyResources setting no strategy", func() {
+		parents, err := Decode(strings.NewReader(`apiVersion: sample.k8s-controller.ad/v1
+kind: MyResource
+metadata:
+  name: v1-without-strategy
+spec:
+  children:
+  - name: a
+---
+apiVersion: sample.k8s-controller.ad/v2
+kind: MyResource
+metadata:
+  name: v2-without-strategy
+spec:
+  children:
+  - metadata:
+      name: b
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parents).To(HaveLen(2))
+
+		for _, parent := range parents {
+			Expect(parent.Spec.Strategy).To(BeEmpty())
+			children, err := Render(ctx, parent.DeepCopy(), opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(children[0].Annotations).To(HaveKeyWithValue(controller.AnnotationStrategy, "Suggested"))
+
+			withDefault := opts
+			withDefault.DefaultStrategy = samplev1.ApplyStrategyPatch
+			children, err = Render(ctx, parent.DeepCopy(), withDefault)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(children[0].Annotations).To(HaveKeyWithValue(controller.AnnotationStrategy, "Patch"))
+		}
+
+		By("keeping the strategy set by a MyResource")
+		parents, err = Decode(strings.NewReader(manifests))
+		Expect(err).NotTo(HaveOccurred())
+		opts.DefaultStrategy = samplev1.ApplyStrategyPatch
+		children, err := Render(ctx, parents[0], opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(children[0].Annotations).To(HaveKeyWithValue(controller.AnnotationStrategy, "SSA"))
+	})
+
+	It("should reject MyResources the validating webhook rejects", func() {
+		parent := &samplev1.MyResource{Spec: samplev1.MyResourceSpec{Children: []samplev1.ChildTemplate{
+			{Name: "a", Namespace: "other"},
+		}}}
+		_, err := Render(ctx, parent, opts)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "%v", err)
+
+		opts.Webhook.NamespaceAllowList = controller.NamespaceAllowList{"default": {"other"}}
+		Expect(Render(ctx, parent, opts)).To(HaveLen(1))
+	})
+
+	It("should refuse rollbacks, which need the revisions of the cluster", func() {
+		parent := &samplev1.MyResource{Spec: samplev1.MyResourceSpec{RollbackTo: &samplev1.RollbackConfig{Revision: 1}}}
+		_, err := Render(ctx, parent, opts)
+		Expect(err).To(MatchError(ContainSubstring("cannot be rendered offline")))
+	})
+
+	It("should write YAML documents or a JSON list", func() {
+		parents, err := Decode(strings.NewReader(manifests))
+		Expect(err).NotTo(HaveOccurred())
+		children, err := Render(ctx, parents[0], opts)
+		Expect(err).NotTo(HaveOccurred())
+
+		out := &strings.Builder{}
+		Expect(Write(out, children, FormatYAML)).To(Succeed())
+		Expect(out.String()).To(HavePrefix("apiVersion: sample.k8s-controller.ad/v1\nkind: MyChildResource\n"))
+		Expect(strings.Count(out.String(), "\n---\n")).To(Equal(1))
+
+		out.Reset()
+		Expect(Write(out, children, FormatJSON)).To(Succeed())
+		Expect(out.String()).To(HavePrefix("{\n    \"apiVersion\": \"v1\",\n    \"kind\": \"List\",\n    \"items\": [\n"))
+		Expect(out.String()).To(ContainSubstring(`"name": "b"`))
+
+		out.Reset()
+		Expect(Write(out, nil, FormatJSON)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`"items": []`))
+		Expect(Write(out, nil, "table")).To(MatchError(ContainSubstring("unsupported output format")))
+	})
+})
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// virtualNodes is the number of points of every member on the ring. More
+// points spread keys more evenly between members.
+const virtualNodes = 128
+
+// Ring assigns keys to members by consistent hashing: adding or removing a
+// member only moves the keys it gains or loses. A Ring is immutable.
+type Ring struct {
+	members []string
+	points  []uint64
+	owners  map[uint64]string
+}
+
+// NewRing returns the ring of members.
+func NewRing(members []string) *Ring {
+	r := &Ring{
+		members: slices.Sorted(slices.Values(members)),
+		owners:  make(map[uint64]string, len(members)*virtualNodes),
+	}
+	r.members = slices.Compact(r.members)
+	for _, member := range r.members {
+		for i := 0; i < virtualNodes; i++ {
+			point := hash(member + "#" + strconv.Itoa(i))
+			if _, taken := r.owners[point]; taken {
+				continue
+			}
+			r.owners[point] = member
+			r.points = append(r.points, point)
+		}
+	}
+	slices.Sort(r.points)
+	return r
+}
+
+// Members returns the sorted members of the ring.
+func (r *Ring) Members() []string {
+	return slices.Clone(r.members)
+}
+
+// Owner returns the member owning key, or "" when the ring is empty.
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	point := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= point })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// hash places value on the ring. Unlike FNV, SHA-256 spreads the points of
+// similar values such as the virtual nodes of a member evenly.
+func hash(value string) uint64 {
+	sum := sha256.Sum256([]byte(value))
+	return binary.BigEndian.Uint64(sum[:8])
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ring", func() {
+	keys := make([]string, 3000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("namespace-%d/parent-%d", i%17, i)
+	}
+
+	It("should own nothing without members", func() {
+		Expect(NewRing(nil).Owner("default/parent")).To(BeEmpty())
+	})
+
+	It("should spread keys evenly across members", func() {
+		ring := NewRing([]string{"replica-a", "replica-b", "replica-c"})
+		owned := map[string]int{}
+		for _, key := range keys {
+			owned[ring.Owner(key)]++
+		}
+		Expect(owned).To(HaveLen(3))
+		for member, count := range owned {
+			Expect(count).To(BeNumerically("~", len(keys)/3, len(keys)/6), member)
+		}
+	})
+
+	It("should only move the keys of a joining or leaving member", func() {
+		before := NewRing([]string{"replica-a", "replica-b", "replica-c"})
+		after := NewRing([]string{"replica-a", "replica-b", "replica-c", "replica-d"})
+		moved := 0
+		for _, key := range keys {
+			if owner := after.Owner(key); owner != before.Owner(key) {
+				Expect(owner).To(Equal("replica-d"))
+				moved++
+			}
+		}
+		Expect(moved).To(BeNumerically("~", len(keys)/4, len(keys)/8))
+	})
+
+	It("should not depend on the order of members", func() {
+		Expect(NewRing([]string{"b", "a", "a"}).Members()).To(Equal([]string{"a", "b"}))
+		Expect(NewRing([]string{"b", "a"}).Owner("key")).To(Equal(NewRing([]string{"a", "b"}).Owner("key")))
+	})
+})
@@ -0,0 +1,330 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding spreads the parents of the controller across manager
+// replicas. Every replica holds a shard lease; the replicas with a live lease
+// form a consistent hashing ring assigning each parent to one of them.
+//
+// Replicas see a change of the ring at different times, so the ring alone
+// does not keep two replicas from working on the same parent. A replica only
+// works on a parent whose LabelShard it holds: it claims the label of a parent
+// that is not labelled or labelled by a replica without a live lease, and
+// removes its label from the parents it lost, which hands them over.
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// LabelShard records on a parent the replica reconciling it. A replica only
+	// reconciles the parents it claimed with this label.
+	LabelShard = "sample.k8s-controller.ad/shard"
+	// LabelRing selects the shard leases of a ring.
+	LabelRing = "sample.k8s-controller.ad/shard-ring"
+
+	// DefaultLeaseDuration is the time after which the lease of a replica
+	// that stopped renewing it expires and its parents move to other replicas.
+	DefaultLeaseDuration = 30 * time.Second
+	// DefaultRenewInterval is the interval at which a replica renews its lease
+	// and refreshes the ring.
+	DefaultRenewInterval = 10 * time.Second
+)
+
+// Options configures a Shard.
+type Options struct {
+	// Namespace is the namespace of the shard leases.
+	Namespace string
+	// Ring names the ring, so that several controllers may shard in the same
+	// namespace.
+	Ring string
+	// Identity is the unique name of the replica, typically its pod name.
+	Identity string
+	// LeaseDuration defaults to DefaultLeaseDuration.
+	LeaseDuration time.Duration
+	// RenewInterval defaults to DefaultRenewInterval.
+	RenewInterval time.Duration
+}
+
+// Shard is the membership of a replica in a ring. It is a manager Runnable
+// renewing the lease of the replica and refreshing the ring from the leases of
+// all replicas.
+type Shard struct {
+	client client.Client
+	opts   Options
+	now    func() time.Time
+
+	changed chan struct{}
+
+	mu        sync.RWMutex
+	ring      *Ring
+	lastRenew time.Time
+	stopping  bool
+
+	// inflight counts the keys acquired, which a stopping replica waits for
+	// before releasing its lease.
+	inflight sync.WaitGroup
+}
+
+// NewShard returns the Shard of the replica opts.Identity, writing its lease
+// with c.
+func NewShard(c client.Client, opts Options) *Shard {
+	if opts.LeaseDuration <= 0 {
+		opts.LeaseDuration = DefaultLeaseDuration
+	}
+	if opts.RenewInterval <= 0 {
+		opts.RenewInterval = DefaultRenewInterval
+	}
+	return &Shard{
+		client:  c,
+		opts:    opts,
+		now:     time.Now,
+		changed: make(chan struct{}, 1),
+		ring:    NewRing(nil),
+	}
+}
+
+// Identity returns the name of the replica.
+func (s *Shard) Identity() string {
+	return s.opts.Identity
+}
+
+// Changed signals every change of the ring, coalescing changes that were not
+// received yet.
+func (s *Shard) Changed() <-chan struct{} {
+	return s.changed
+}
+
+// Owns reports whether key is assigned to this replica. Nothing is owned
+// while the lease of the replica is expired, since other replicas have taken
+// over its keys by then, nor once the replica is stopping.
+func (s *Shard) Owns(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.owns(key)
+}
+
+func (s *Shard) owns(key string) bool {
+	if s.stopping || s.now().Sub(s.lastRenew) > s.opts.LeaseDuration {
+		return false
+	}
+	return s.ring.Owner(key) == s.opts.Identity
+}
+
+// Acquire reports whether key is assigned to this replica, like Owns. An
+// acquired key keeps the lease of a stopping replica from being released
+// until done is called, so that no other replica takes the key over while it
+// is worked on.
+func (s *Shard) Acquire(key string) (done func(), ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.owns(key) {
+		return nil, false
+	}
+	s.inflight.Add(1)
+	return s.inflight.Done, true
+}
+
+// Members returns the replicas of the current ring.
+func (s *Shard) Members() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ring.Members()
+}
+
+// IsMember reports whether identity held a live lease at the last refresh of
+// the ring. The keys labelled by a member are only taken over once it removed
+// its label.
+func (s *Shard) IsMember(identity string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Contains(s.ring.members, identity)
+}
+
+// Start renews the lease and refreshes the ring until ctx is done. It then
+// stops acquiring keys and waits for the keys acquired before to be done, up
+// to RenewInterval, to release the lease so that the other replicas take over
+// right away. A lease whose keys are still worked on is left to expire.
+func (s *Shard) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithValues("shard", s.opts.Identity)
+	ticker := time.NewTicker(s.opts.RenewInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.renew(ctx); err != nil {
+			logger.Error(err, "Unable to renew the shard lease")
+		}
+		if err := s.refresh(ctx); err != nil {
+			logger.Error(err, "Unable to refresh the shard ring")
+		}
+
+		select {
+		case <-ctx.Done():
+			if !s.drain(s.opts.RenewInterval) {
+				logger.Info("Leaving the shard lease to expire, keys are still worked on")
+				return nil
+			}
+			releaseCtx, cancel := context.WithTimeout(context.Background(), s.opts.RenewInterval)
+			defer cancel()
+			if err := s.release(releaseCtx); err != nil {
+				logger.Error(err, "Unable to release the shard lease")
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain stops acquiring keys and reports whether the keys acquired before
+// were done within timeout.
+func (s *Shard) drain(timeout time.Duration) bool {
+	s.mu.Lock()
+	s.stopping = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// NeedLeaderElection lets every replica join the ring.
+func (s *Shard) NeedLeaderElection() bool {
+	return false
+}
+
+// leaseName returns the name of the lease of the replica.
+func (s *Shard) leaseName() string {
+	return fmt.Sprintf("%s-shard-%s", s.opts.Ring, s.opts.Identity)
+}
+
+// renew creates or renews the lease of the replica.
+func (s *Shard) renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(s.now())
+	lease := &coordinationv1.Lease{}
+	err := s.client.Get(ctx, client.ObjectKey{Namespace: s.opts.Namespace, Name: s.leaseName()}, lease)
+	switch {
+	case apierrors.IsNotFound(err):
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.opts.Namespace,
+				Name:      s.leaseName(),
+				Labels:    map[string]string{LabelRing: s.opts.Ring},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.opts.Identity),
+				LeaseDurationSeconds: ptr.To(int32(s.opts.LeaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		err = s.client.Create(ctx, lease)
+	case err == nil:
+		lease.Spec.HolderIdentity = ptr.To(s.opts.Identity)
+		lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.opts.LeaseDuration.Seconds()))
+		lease.Spec.RenewTime = &now
+		err = s.client.Update(ctx, lease)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRenew = now.Time
+	return nil
+}
+
+// refresh rebuilds the ring from the unexpired leases and signals a change
+// of its members. The expired leases of other replicas are deleted, since
+// replicas that did not stop cleanly, e.g. pods replaced by a rollout, never
+// come back to them.
+func (s *Shard) refresh(ctx context.Context) error {
+	leases := &coordinationv1.LeaseList{}
+	if err := s.client.List(ctx, leases, client.InNamespace(s.opts.Namespace),
+		client.MatchingLabels{LabelRing: s.opts.Ring}); err != nil {
+		return err
+	}
+
+	now := s.now()
+	var members []string
+	for _, lease := range leases.Items {
+		spec := lease.Spec
+		if spec.HolderIdentity == nil || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		if now.After(spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)) {
+			if *spec.HolderIdentity != s.opts.Identity {
+				if err := s.deleteExpired(ctx, &lease); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		members = append(members, *spec.HolderIdentity)
+	}
+
+	ring := NewRing(members)
+	s.mu.Lock()
+	changed := !slices.Equal(ring.Members(), s.ring.Members())
+	if changed {
+		s.ring = ring
+	}
+	s.mu.Unlock()
+
+	if changed {
+		log.FromContext(ctx).Info("Shard ring changed", "members", ring.Members())
+		select {
+		case s.changed <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// deleteExpired deletes the expired lease of another replica, unless the
+// replica renewed it in the meantime.
+func (s *Shard) deleteExpired(ctx context.Context, lease *coordinationv1.Lease) error {
+	log.FromContext(ctx).Info("Deleting expired shard lease", "lease", lease.Name, "holder", *lease.Spec.HolderIdentity)
+	err := s.client.Delete(ctx, lease, client.Preconditions{ResourceVersion: ptr.To(lease.ResourceVersion)})
+	if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
+
+// release deletes the lease of the replica.
+func (s *Shard) release(ctx context.Context) error {
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: s.opts.Namespace, Name: s.leaseName()}}
+	return client.IgnoreNotFound(s.client.Delete(ctx, lease))
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Shard", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+	)
+
+	peerLease := func(identity string, renewed time.Time) *coordinationv1.Lease {
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "system",
+				Name:      "myresource-shard-" + identity,
+				Labels:    map[string]string{LabelRing: "myresource"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(identity),
+				LeaseDurationSeconds: ptr.To[int32](30),
+				RenewTime:            &metav1.MicroTime{Time: renewed},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+			peerLease("replica-b", time.Now()),
+			peerLease("replica-gone", time.Now().Add(-time.Minute)),
+		).Build()
+	})
+
+	newShard := func() *Shard {
+		return NewShard(c, Options{Namespace: "system", Ring: "myresource", Identity: "replica-a"})
+	}
+
+	It("should join the ring of the replicas with a live lease", func() {
+		shard := newShard()
+		Expect(shard.Owns("default/parent")).To(BeFalse())
+
+		Expect(shard.renew(ctx)).To(Succeed())
+		Expect(shard.refresh(ctx)).To(Succeed())
+		Expect(shard.Members()).To(Equal([]string{"replica-a", "replica-b"}))
+		Expect(shard.IsMember("replica-b")).To(BeTrue())
+		Expect(shard.IsMember("replica-gone")).To(BeFalse())
+		Eventually(shard.Changed()).Should(Receive())
+
+		lease := &coordinationv1.Lease{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "system", Name: "myresource-shard-replica-a"}, lease)).To(Succeed())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("replica-a"))
+
+		ring := NewRing([]string{"replica-a", "replica-b"})
+		for _, key := range []string{"default/a", "default/b", "default/c", "team/d", "team/e"} {
+			Expect(shard.Owns(key)).To(Equal(ring.Owner(key) == "replica-a"), key)
+		}
+
+		By("not signalling an unchanged ring")
+		Expect(shard.refresh(ctx)).To(Succeed())
+		Consistently(shard.Changed()).ShouldNot(Receive())
+	})
+
+	It("should delete the expired leases of other replicas", func() {
+		shard := newShard()
+		Expect(shard.renew(ctx)).To(Succeed())
+		Expect(shard.refresh(ctx)).To(Succeed())
+
+		err := c.Get(ctx, client.ObjectKey{Namespace: "system", Name: "myresource-shard-replica-gone"}, &coordinationv1.Lease{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "system", Name: "myresource-shard-replica-b"}, &coordinationv1.Lease{})).To(Succeed())
+
+		By("keeping its own expired lease to renew it")
+		shard.now = func() time.Time { return time.Now().Add(time.Minute) }
+		Expect(shard.refresh(ctx)).To(Succeed())
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "system", Name: "myresource-shard-replica-a"}, &coordinationv1.Lease{})).To(Succeed())
+	})
+
+	It("should own nothing once its own lease expired", func() {
+		shard := newShard()
+		Expect(shard.renew(ctx)).To(Succeed())
+		Expect(shard.refresh(ctx)).To(Succeed())
+		Expect(NewRing(shard.Members()).Owner("default/a")).NotTo(BeEmpty())
+
+		shard.now = func() time.Time { return time.Now().Add(time.Minute) }
+		for _, key := range []string{"default/a", "default/b", "default/c", "team/d", "team/e"} {
+			Expect(shard.Owns(key)).To(BeFalse())
+		}
+	})
+
+	It("should release its lease when stopped", func() {
+		shard := newShard()
+		shard.opts.RenewInterval = 10 * time.Millisecond
+		startCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(shard.Start(startCtx)).To(Succeed())
+		}()
+		Eventually(shard.Members).Should(ContainElement("replica-a"))
+
+		cancel()
+		Eventually(done).Should(BeClosed())
+		err := c.Get(ctx, client.ObjectKey{Namespace: "system", Name: "myresource-shard-replica-a"}, &coordinationv1.Lease{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should hand over its keys before releasing its lease when stopped", func() {
+		shard := newShard()
+		shard.opts.RenewInterval = time.Second
+		Expect(shard.renew(ctx)).To(Succeed())
+		Expect(shard.refresh(ctx)).To(Succeed())
+		key := "default/a"
+		for i := 0; shard.ring.Owner(key) != "replica-a"; i++ {
+			key = fmt.Sprintf("default/%d", i)
+		}
+		release, ok := shard.Acquire(key)
+		Expect(ok).To(BeTrue())
+
+		startCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(shard.Start(startCtx)).To(Succeed())
+		}()
+		cancel()
+
+		By("acquiring no more keys while stopping")
+		Eventually(func() bool { return shard.Owns(key) }).Should(BeFalse())
+		_, ok = shard.Acquire(key)
+		Expect(ok).To(BeFalse())
+
+		By("keeping its lease until the acquired key is done")
+		Consistently(done, 100*time.Millisecond).ShouldNot(BeClosed())
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: "system", Name: "myresource-shard-replica-a"}, &coordinationv1.Lease{})).To(Succeed())
+
+		release()
+		Eventually(done).Should(BeClosed())
+		err := c.Get(ctx, client.ObjectKey{Namespace: "system", Name: "myresource-shard-replica-a"}, &coordinationv1.Lease{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NewClient returns c with a span around every API call, named after the
+// verb and carrying the kind, namespace and name of the object and the
+// outcome of the call. Spans are children of the span found in the context.
+func NewClient(c client.Client) client.Client {
+	return &tracingClient{Client: c}
+}
+
+type tracingClient struct {
+	client.Client
+}
+
+func (c *tracingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	ctx, span := c.start(ctx, "Get", obj, key.Namespace, key.Name)
+	err := c.Client.Get(ctx, key, obj, opts...)
+	end(span, err)
+	return err
+}
+
+func (c *tracingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+	ctx, span := c.start(ctx, "List", list, listOpts.Namespace, "")
+	err := c.Client.List(ctx, list, opts...)
+	end(span, err)
+	return err
+}
+
+func (c *tracingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, span := c.start(ctx, "Create", obj, obj.GetNamespace(), obj.GetName())
+	err := c.Client.Create(ctx, obj, opts...)
+	end(span, err)
+	return err
+}
+
+func (c *tracingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, span := c.start(ctx, "Update", obj, obj.GetNamespace(), obj.GetName())
+	err := c.Client.Update(ctx, obj, opts...)
+	end(span, err)
+	return err
+}
+
+func (c *tracingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, span := c.start(ctx, fmt.Sprintf("Patch %s", patch.Type()), obj, obj.GetNamespace(), obj.GetName())
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	end(span, err)
+	return err
+}
+
+func (c *tracingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, span := c.start(ctx, "Delete", obj, obj.GetNamespace(), obj.GetName())
+	err := c.Client.Delete(ctx, obj, opts...)
+	end(span, err)
+	return err
+}
+
+func (c *tracingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	deleteOpts := (&client.DeleteAllOfOptions{}).ApplyOptions(opts)
+	ctx, span := c.start(ctx, "DeleteAllOf", obj, deleteOpts.Namespace, "")
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+	end(span, err)
+	return err
+}
+
+func (c *tracingClient) Status() client.SubResourceWriter {
+	return &tracingStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+// tracingStatusWriter traces the status updates of tracingClient.
+type tracingStatusWriter struct {
+	client.SubResourceWriter
+	client *tracingClient
+}
+
+func (w *tracingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	ctx, span := w.client.start(ctx, "Update status", obj, obj.GetNamespace(), obj.GetName())
+	err := w.SubResourceWriter.Update(ctx, obj, opts...)
+	end(span, err)
+	return err
+}
+
+func (w *tracingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	ctx, span := w.client.start(ctx, fmt.Sprintf("Patch %s status", patch.Type()), obj, obj.GetNamespace(), obj.GetName())
+	err := w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+	end(span, err)
+	return err
+}
+
+// start starts the span of an API call on obj.
+func (c *tracingClient) start(ctx context.Context, verb string, obj runtime.Object, namespace, name string) (context.Context, trace.Span) {
+	kind := fmt.Sprintf("%T", obj)
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		kind = gvk.Kind
+	}
+	return Tracer().Start(ctx, verb+" "+kind, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		KindKey.String(kind),
+		NamespaceKey.String(namespace),
+		NameKey.String(name),
+	))
+}
+
+// end records the outcome of an API call on span and ends it. NotFound is an
+// expected outcome of reads and not recorded as an error.
+func end(span trace.Span, err error) {
+	reason := metav1.StatusReason("Success")
+	if err != nil {
+		reason = apierrors.ReasonForError(err)
+		if reason == metav1.StatusReasonUnknown {
+			reason = "Error"
+		}
+	}
+	span.SetAttributes(OutcomeKey.String(string(reason)))
+	if apierrors.IsNotFound(err) {
+		err = nil
+	}
+	End(span, err)
+}
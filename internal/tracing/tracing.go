@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing sets up OpenTelemetry tracing of the manager and traces the
+// API calls made through a client.Client.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer of the controller.
+const TracerName = "k8s-controller.ad"
+
+// Attributes set on the spans of the controller.
+const (
+	ParentNamespaceKey = attribute.Key("myresource.namespace")
+	ParentNameKey      = attribute.Key("myresource.name")
+	ChildNamespaceKey  = attribute.Key("mychildresource.namespace")
+	ChildNameKey       = attribute.Key("mychildresource.name")
+	ChildClusterKey    = attribute.Key("mychildresource.cluster")
+	StrategyKey        = attribute.Key("myresource.strategy")
+	OutcomeKey         = attribute.Key("outcome")
+
+	KindKey      = attribute.Key("k8s.kind")
+	NamespaceKey = attribute.Key("k8s.namespace")
+	NameKey      = attribute.Key("k8s.name")
+)
+
+// Options configures the export of traces.
+type Options struct {
+	// Endpoint is the host:port of the OTLP gRPC receiver. Tracing is
+	// disabled when it is empty.
+	Endpoint string
+	// Insecure disables TLS towards Endpoint.
+	Insecure bool
+	// SampleRatio is the ratio of new traces sampled, between 0 and 1.
+	SampleRatio float64
+	// ServiceName is the service.name resource attribute of the traces.
+	ServiceName string
+}
+
+// Setup installs the global tracer provider exporting to opts.Endpoint and
+// returns the function flushing and stopping it. Nothing is installed when no
+// endpoint is configured, leaving the no-op provider in place.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(opts.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the controller from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
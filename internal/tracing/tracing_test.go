@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// collector is an in-process OTLP trace receiver keeping every span.
+type collector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	spans []*tracev1.Span
+}
+
+func (c *collector) Export(_ context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, resourceSpans := range req.ResourceSpans {
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			c.spans = append(c.spans, scopeSpans.Spans...)
+		}
+	}
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+// attributes returns the string attributes of the span named name.
+func (c *collector) attributes(name string) map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, span := range c.spans {
+		if span.Name != name {
+			continue
+		}
+		attributes := map[string]string{}
+		for _, kv := range span.Attributes {
+			attributes[kv.Key] = kv.Value.GetStringValue()
+		}
+		return attributes
+	}
+	return nil
+}
+
+var _ = Describe("Tracing", func() {
+	It("should be disabled without an endpoint", func() {
+		shutdown, err := Setup(context.Background(), Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shutdown(context.Background())).To(Succeed())
+	})
+
+	It("should export a span per API call to the OTLP endpoint", func() {
+		ctx := context.Background()
+
+		By("starting an in-process collector")
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		received := &collector{}
+		server := grpc.NewServer()
+		collectortrace.RegisterTraceServiceServer(server, received)
+		go func() { _ = server.Serve(listener) }()
+		DeferCleanup(server.Stop)
+
+		provider := otel.GetTracerProvider()
+		DeferCleanup(func() { otel.SetTracerProvider(provider) })
+		shutdown, err := Setup(ctx, Options{
+			Endpoint:    listener.Addr().String(),
+			Insecure:    true,
+			SampleRatio: 1,
+			ServiceName: "tracing-test",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("calling the API through a traced client")
+		c := NewClient(fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "traced", Namespace: "default"},
+		}).Build())
+
+		spanCtx, span := Tracer().Start(ctx, "Reconcile MyResource")
+		Expect(c.Get(spanCtx, types.NamespacedName{Name: "traced", Namespace: "default"}, &corev1.ConfigMap{})).To(Succeed())
+		Expect(c.Get(spanCtx, types.NamespacedName{Name: "missing", Namespace: "default"}, &corev1.Secret{})).NotTo(Succeed())
+		span.End()
+		Expect(shutdown(ctx)).To(Succeed())
+
+		Expect(received.attributes("Reconcile MyResource")).NotTo(BeNil())
+		Expect(received.attributes("Get ConfigMap")).To(Equal(map[string]string{
+			string(KindKey):      "ConfigMap",
+			string(NamespaceKey): "default",
+			string(NameKey):      "traced",
+			string(OutcomeKey):   "Success",
+		}))
+		Expect(received.attributes("Get Secret")).To(HaveKeyWithValue(string(OutcomeKey), "NotFound"))
+	})
+})
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+// ChildDeletionProtectionPath serves the child deletion protection webhook.
+const ChildDeletionProtectionPath = "/validate-child-deletion"
+
+// deletionExemptUsers delete children on behalf of the cluster: the garbage
+// collector, with and without service account credentials, and the namespace
+// controller, which must empty a namespace being deleted.
+var deletionExemptUsers = []string{
+	"system:serviceaccount:kube-system:generic-garbage-collector",
+	"system:serviceaccount:kube-system:namespace-controller",
+	"system:kube-controller-manager",
+}
+
+var childdeletionlog = logf.Log.WithName("child-deletion-protection")
+
+// SetupChildDeletionProtectionWebhookWithManager registers the child deletion
+// protection webhook in the manager. Parents and their revisions are read
+// from the API server, as the cache of the manager may not watch their
+// namespace.
+func SetupChildDeletionProtectionWebhookWithManager(mgr ctrl.Manager, opts Options) error {
+	mgr.GetWebhookServer().Register(ChildDeletionProtectionPath, &webhook.Admission{
+		Handler: &ChildDeletionProtector{
+			Options: opts,
+			Reader:  mgr.GetAPIReader(),
+			decoder: admission.NewDecoder(mgr.GetScheme()),
+		},
+	})
+	return nil
+}
+
+// The webhook fails open so that children can still be deleted while the
+// controller is unavailable.
+// +kubebuilder:webhook:path=/validate-child-deletion,mutating=false,failurePolicy=ignore,sideEffects=None,groups=sample.k8s-controller.ad,resources=mychildresources,verbs=delete,versions=v1,name=vchilddeletion.kb.io,admissionReviewVersions=v1
+
+// ChildDeletionProtector rejects deletions of children that their parent
+// still desires, which the controller would silently recreate on its next
+// reconcile. The parent of a child is the MyResource named by its tracking
+// labels; children of parents gone, being deleted or no longer desiring them
+// may be deleted.
+//
+// Deletions by the controller, the garbage collector and the namespace
+// controller are admitted.
+type ChildDeletionProtector struct {
+	Options
+	// Reader reads parents and their revisions.
+	Reader  client.Reader
+	decoder admission.Decoder
+}
+
+// Handle implements admission.Handler.
+func (p *ChildDeletionProtector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Delete || req.SubResource != "" {
+		return admission.Allowed("")
+	}
+	if req.UserInfo.Username == p.ControllerUsername || slices.Contains(deletionExemptUsers, req.UserInfo.Username) {
+		return admission.Allowed("")
+	}
+
+	child := &unstructured.Unstructured{}
+	if err := p.decoder.DecodeRaw(req.OldObject, child); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	labels := child.GetLabels()
+	key := types.NamespacedName{Namespace: labels[controller.LabelParentNamespace], Name: labels[controller.LabelParentName]}
+	if key.Namespace == "" || key.Name == "" {
+		return admission.Allowed("")
+	}
+
+	parent := &samplev1.MyResource{}
+	if err := p.Reader.Get(ctx, key, parent); err != nil {
+		if apierrors.IsNotFound(err) {
+			return admission.Allowed("")
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if string(parent.UID) != labels[controller.LabelParentUID] || !parent.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+
+	// Which children are desired does not depend on their strategy.
+	desired, err := controller.DesiredChildren(ctx, p.Reader, parent, "")
+	if err != nil {
+		// The controller cannot recreate children of a parent it fails to
+		// render either.
+		childdeletionlog.Info("Admitting the deletion of a child of a parent failing to render",
+			"namespace", req.Namespace, "name", req.Name, "parent", key, "error", err.Error())
+		return admission.Allowed("")
+	}
+	for _, d := range desired {
+		if d.Namespace == req.Namespace && d.Name == req.Name && d.Annotations[controller.AnnotationCluster] == "" {
+			return admission.Denied(fmt.Sprintf(
+				"%s %s/%s is desired by MyResource %s, which would recreate it: "+
+					"remove it from the children of MyResource %s instead",
+				req.Kind.Kind, req.Namespace, req.Name, key, key))
+		}
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+var _ = Describe("Child deletion protection Webhook", func() {
+	const controllerUser = "system:serviceaccount:system:controller-manager"
+
+	var (
+		parent    *samplev1.MyResource
+		child     *samplev1.MyChildResource
+		protector *ChildDeletionProtector
+		username  string
+	)
+
+	// handle sends the deletion of child by username to protector, with
+	// parent stored unless nil.
+	handle := func() admission.Response {
+		builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+		if parent != nil {
+			builder = builder.WithObjects(parent)
+		}
+		protector.Reader = builder.Build()
+		raw, err := json.Marshal(child)
+		Expect(err).NotTo(HaveOccurred())
+		return protector.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: "sample.k8s-controller.ad", Version: "v1", Kind: "MyChildResource"},
+			Namespace: child.Namespace,
+			Name:      child.Name,
+			Operation: admissionv1.Delete,
+			UserInfo:  authenticationv1.UserInfo{Username: username},
+			OldObject: runtime.RawExtension{Raw: raw},
+		}})
+	}
+
+	BeforeEach(func() {
+		parent = &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "parent", UID: "parent-uid"},
+			Spec: samplev1.MyResourceSpec{
+				Children: []samplev1.ChildTemplate{
+					{Name: "desired"},
+					{Name: "remote", Cluster: "east"},
+				},
+			},
+		}
+		child = &samplev1.MyChildResource{
+			TypeMeta: metav1.TypeMeta{APIVersion: "sample.k8s-controller.ad/v1", Kind: "MyChildResource"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "desired",
+				Labels: map[string]string{
+					controller.LabelParentNamespace: "default",
+					controller.LabelParentName:      "parent",
+					controller.LabelParentUID:       "parent-uid",
+				},
+			},
+		}
+		protector = &ChildDeletionProtector{
+			Options: Options{ControllerUsername: controllerUser},
+			decoder: admission.NewDecoder(scheme.Scheme),
+		}
+		username = "alice"
+	})
+
+	It("Should deny deleting a child its parent desires", func() {
+		response := handle()
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring("MyChildResource default/desired"))
+		Expect(response.Result.Message).To(ContainSubstring("remove it from the children of MyResource default/parent"))
+	})
+
+	It("Should admit deletions by the controller and the garbage collector", func() {
+		username = controllerUser
+		Expect(handle().Allowed).To(BeTrue())
+		username = "system:serviceaccount:kube-system:generic-garbage-collector"
+		Expect(handle().Allowed).To(BeTrue())
+	})
+
+	It("Should admit deleting a child its parent no longer desires", func() {
+		parent.Spec.Children = parent.Spec.Children[1:]
+		Expect(handle().Allowed).To(BeTrue())
+	})
+
+	It("Should admit deleting a local namesake of a remote child", func() {
+		child.Name = "remote"
+		Expect(handle().Allowed).To(BeTrue())
+	})
+
+	It("Should admit deleting a child whose parent is gone or replaced", func() {
+		parent.UID = "other-uid"
+		Expect(handle().Allowed).To(BeTrue())
+		parent = nil
+		Expect(handle().Allowed).To(BeTrue())
+	})
+
+	It("Should admit deleting a child without tracking labels", func() {
+		child.Labels = nil
+		Expect(handle().Allowed).To(BeTrue())
+	})
+})
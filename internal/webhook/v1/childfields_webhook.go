@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+
+	"k8s-controller.ad/internal/controller"
+	"k8s-controller.ad/internal/features"
+)
+
+const (
+	// ChildFieldProtectionPath serves the child field protection webhook.
+	ChildFieldProtectionPath = "/validate-child-fields"
+
+	// AnnotationAllowManualEdits set to "true" on a child lets every user
+	// change the fields applied by the controller, which still reverts them
+	// on its next reconcile.
+	AnnotationAllowManualEdits = "sample.k8s-controller.ad/allow-manual-edits"
+)
+
+var childfieldslog = logf.Log.WithName("child-field-protection")
+
+// SetupChildFieldProtectionWebhookWithManager registers the child field
+// protection webhook in the manager. It only rejects updates while the
+// ProtectChildFields feature gate is enabled.
+func SetupChildFieldProtectionWebhookWithManager(mgr ctrl.Manager, opts Options) error {
+	mgr.GetWebhookServer().Register(ChildFieldProtectionPath, &webhook.Admission{
+		Handler: &ChildFieldProtector{Options: opts, decoder: admission.NewDecoder(mgr.GetScheme())},
+	})
+	return nil
+}
+
+// The webhook fails open: the controller reverts manual edits anyway, so an
+// unavailable webhook must not block children updates. Other kinds of
+// children are protected by adding them to the rules of the webhook.
+// +kubebuilder:webhook:path=/validate-child-fields,mutating=false,failurePolicy=ignore,sideEffects=None,groups=sample.k8s-controller.ad,resources=mychildresources,verbs=update,versions=v1,name=vchildfields.kb.io,admissionReviewVersions=v1
+
+// ChildFieldProtector rejects updates of children changing fields applied by
+// the controller, which the controller would revert on its next reconcile.
+// The fields of a child applied by the controller are the ones its
+// managedFields assign to controller.ManagerName. It handles children of
+// any kind, decoded as unstructured objects.
+//
+// Updates by the controller and by FieldProtectionBypassUsers are admitted,
+// as are updates of children annotated with AnnotationAllowManualEdits=true.
+type ChildFieldProtector struct {
+	Options
+	decoder admission.Decoder
+}
+
+// Handle implements admission.Handler.
+func (p *ChildFieldProtector) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Update || req.SubResource != "" || !features.Enabled(p.Features, features.ProtectChildFields) {
+		return admission.Allowed("")
+	}
+	if req.UserInfo.Username == p.ControllerUsername {
+		return admission.Allowed("")
+	}
+	if slices.Contains(p.FieldProtectionBypassUsers, req.UserInfo.Username) {
+		childfieldslog.Info("Bypassing the child field protection", "user", req.UserInfo.Username,
+			"kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name)
+		return admission.Allowed("")
+	}
+
+	oldChild, newChild := &unstructured.Unstructured{}, &unstructured.Unstructured{}
+	if err := p.decoder.DecodeRaw(req.OldObject, oldChild); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if err := p.decoder.DecodeRaw(req.Object, newChild); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if newChild.GetAnnotations()[AnnotationAllowManualEdits] == "true" {
+		return admission.Allowed("").WithWarnings(fmt.Sprintf(
+			"fields applied by the controller are not protected on children annotated with %s=true", AnnotationAllowManualEdits))
+	}
+
+	changed, err := controllerFieldsChanged(oldChild, newChild)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(changed) == 0 {
+		return admission.Allowed("")
+	}
+	return admission.Denied(fmt.Sprintf(
+		"%s %s/%s: fields applied by the controller (field manager %s) cannot be changed by %s: %s. "+
+			"Change the child template of %s instead, or annotate the child with %s=true",
+		req.Kind.Kind, req.Namespace, req.Name, controller.ManagerName, req.UserInfo.Username,
+		strings.Join(changed, ", "), parentOf(oldChild), AnnotationAllowManualEdits))
+}
+
+// controllerFieldsChanged returns the paths of the fields of oldChild owned
+// by controller.ManagerName that newChild changes or removes.
+func controllerFieldsChanged(oldChild, newChild *unstructured.Unstructured) ([]string, error) {
+	owned := &fieldpath.Set{}
+	for _, entry := range oldChild.GetManagedFields() {
+		if entry.Manager != controller.ManagerName || entry.Operation != metav1.ManagedFieldsOperationApply ||
+			entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("managed fields of %s: %w", entry.Manager, err)
+		}
+		owned = owned.Union(fields)
+	}
+	if owned.Empty() {
+		return nil, nil
+	}
+
+	oldValue, err := typed.DeducedParseableType.FromUnstructured(oldChild.Object)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := typed.DeducedParseableType.FromUnstructured(newChild.Object)
+	if err != nil {
+		return nil, err
+	}
+	comparison, err := oldValue.Compare(newValue)
+	if err != nil {
+		return nil, err
+	}
+	changed := owned.Intersection(comparison.Modified.Union(comparison.Removed))
+
+	var paths []string
+	changed.Iterate(func(path fieldpath.Path) {
+		paths = append(paths, path.String())
+	})
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// parentOf names the parent of child from its tracking labels.
+func parentOf(child *unstructured.Unstructured) string {
+	labels := child.GetLabels()
+	if labels[controller.LabelParentName] == "" {
+		return "its parent"
+	}
+	return fmt.Sprintf("MyResource %s/%s", labels[controller.LabelParentNamespace], labels[controller.LabelParentName])
+}
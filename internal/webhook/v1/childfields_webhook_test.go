@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+	"k8s-controller.ad/internal/features"
+)
+
+var _ = Describe("Child field protection Webhook", func() {
+	const controllerUser = "system:serviceaccount:system:controller-manager"
+
+	var (
+		oldObj    *samplev1.MyChildResource
+		obj       *samplev1.MyChildResource
+		protector *ChildFieldProtector
+		username  string
+	)
+
+	// handle sends the update of oldObj to obj by username to protector.
+	handle := func() admission.Response {
+		oldRaw, err := json.Marshal(oldObj)
+		Expect(err).NotTo(HaveOccurred())
+		raw, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		return protector.Handle(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: "sample.k8s-controller.ad", Version: "v1", Kind: "MyChildResource"},
+			Namespace: obj.Namespace,
+			Name:      obj.Name,
+			Operation: admissionv1.Update,
+			UserInfo:  authenticationv1.UserInfo{Username: username},
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+	}
+
+	BeforeEach(func() {
+		gate := features.NewFeatureGate()
+		Expect(gate.Set("ProtectChildFields=true")).To(Succeed())
+		oldObj = &samplev1.MyChildResource{
+			TypeMeta: metav1.TypeMeta{APIVersion: "sample.k8s-controller.ad/v1", Kind: "MyChildResource"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "protected-child",
+				Labels: map[string]string{
+					controller.LabelParentNamespace: "default",
+					controller.LabelParentName:      "parent",
+				},
+				ManagedFields: []metav1.ManagedFieldsEntry{{
+					Manager:    controller.ManagerName,
+					Operation:  metav1.ManagedFieldsOperationApply,
+					APIVersion: "sample.k8s-controller.ad/v1",
+					FieldsType: "FieldsV1",
+					FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:fooList":{},"f:fooMap":{"f:owned":{}}}}`)},
+				}},
+			},
+			Spec: samplev1.MyChildResourceSpec{
+				FooMap:  map[string]string{"owned": "value"},
+				FooList: []string{"a"},
+			},
+		}
+		obj = oldObj.DeepCopy()
+		protector = &ChildFieldProtector{
+			Options: Options{
+				Features:                   gate,
+				ControllerUsername:         controllerUser,
+				FieldProtectionBypassUsers: []string{"system:serviceaccount:ops:break-glass"},
+			},
+			decoder: admission.NewDecoder(scheme.Scheme),
+		}
+		username = "alice"
+	})
+
+	It("Should deny changes of the fields applied by the controller", func() {
+		obj.Spec.FooList = []string{"b"}
+		delete(obj.Spec.FooMap, "owned")
+		response := handle()
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring(".spec.fooList, .spec.fooMap.owned"))
+		Expect(response.Result.Message).To(ContainSubstring("MyResource default/parent"))
+		Expect(response.Result.Message).To(ContainSubstring(AnnotationAllowManualEdits))
+	})
+
+	It("Should admit changes of the other fields", func() {
+		obj.Spec.FooMap["manual"] = "value"
+		obj.Labels["team"] = "a"
+		Expect(handle().Allowed).To(BeTrue())
+	})
+
+	It("Should admit children without fields applied by the controller", func() {
+		oldObj.ManagedFields[0].Manager = "kubectl"
+		obj.Spec.FooList = []string{"b"}
+		Expect(handle().Allowed).To(BeTrue())
+	})
+
+	It("Should admit changes by the controller and the bypass users", func() {
+		obj.Spec.FooList = []string{"b"}
+		username = controllerUser
+		Expect(handle().Allowed).To(BeTrue())
+		username = "system:serviceaccount:ops:break-glass"
+		Expect(handle().Allowed).To(BeTrue())
+	})
+
+	It("Should admit changes of children annotated with the override with a warning", func() {
+		obj.Annotations = map[string]string{AnnotationAllowManualEdits: "true"}
+		obj.Spec.FooList = []string{"b"}
+		response := handle()
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(ConsistOf(ContainSubstring(AnnotationAllowManualEdits)))
+	})
+
+	It("Should admit every change while its gate is disabled", func() {
+		protector.Features = nil
+		obj.Spec.FooList = []string{"b"}
+		Expect(handle().Allowed).To(BeTrue())
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var mychildresourcelog = logf.Log.WithName("mychildresource-resource")
+
+// SetupMyChildResourceWebhookWithManager registers the webhook for MyChildResource in the manager.
+func SetupMyChildResourceWebhookWithManager(mgr ctrl.Manager, opts Options) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&samplev1.MyChildResource{}).
+		WithValidator(&MyChildResourceCustomValidator{Options: opts}).
+		WithDefaulter(&MyChildResourceCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-sample-k8s-controller-ad-v1-mychildresource,mutating=true,failurePolicy=fail,sideEffects=None,groups=sample.k8s-controller.ad,resources=mychildresources,verbs=create;update,versions=v1,name=mmychildresource-v1.kb.io,admissionReviewVersions=v1
+
+// MyChildResourceCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind MyChildResource when those are created or updated.
+//
+// It applies the same defaults as the reconciler applies to rendered
+// children, see MyChildResourceSpec.SetDefaults.
+type MyChildResourceCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &MyChildResourceCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind MyChildResource.
+func (d *MyChildResourceCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	mychildresource, ok := obj.(*samplev1.MyChildResource)
+	if !ok {
+		return fmt.Errorf("expected an MyChildResource object but got %T", obj)
+	}
+	mychildresourcelog.V(1).Info("Defaulting for MyChildResource", "name", mychildresource.GetName())
+
+	mychildresource.Spec.SetDefaults()
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-sample-k8s-controller-ad-v1-mychildresource,mutating=false,failurePolicy=fail,sideEffects=None,groups=sample.k8s-controller.ad,resources=mychildresources,verbs=create;update,versions=v1,name=vmychildresource-v1.kb.io,admissionReviewVersions=v1
+
+// MyChildResourceCustomValidator struct is responsible for validating the MyChildResource resource
+// when it is created, updated, or deleted.
+//
+// It rejects specs breaking the business rules of children and strategy
+// annotations naming an unknown or disabled strategy.
+type MyChildResourceCustomValidator struct {
+	Options
+}
+
+var _ webhook.CustomValidator = &MyChildResourceCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type MyChildResource.
+func (v *MyChildResourceCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	mychildresource, ok := obj.(*samplev1.MyChildResource)
+	if !ok {
+		return nil, fmt.Errorf("expected a MyChildResource object but got %T", obj)
+	}
+	mychildresourcelog.V(1).Info("Validation for MyChildResource upon creation", "name", mychildresource.GetName())
+
+	return nil, v.validate(mychildresource)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type MyChildResource.
+func (v *MyChildResourceCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	mychildresource, ok := newObj.(*samplev1.MyChildResource)
+	if !ok {
+		return nil, fmt.Errorf("expected a MyChildResource object for the newObj but got %T", newObj)
+	}
+	mychildresourcelog.V(1).Info("Validation for MyChildResource upon update", "name", mychildresource.GetName())
+
+	if mychildresource.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	return nil, v.validate(mychildresource)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type MyChildResource.
+func (v *MyChildResourceCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the spec and the strategy annotation of child.
+func (v *MyChildResourceCustomValidator) validate(child *samplev1.MyChildResource) error {
+	allErrs := validateChildSpec(&child.Spec, field.NewPath("spec"))
+	if strategy, ok := child.Annotations[controller.AnnotationStrategy]; ok {
+		path := field.NewPath("metadata", "annotations").Key(controller.AnnotationStrategy)
+		allErrs = append(allErrs, v.validateStrategy(samplev1.ApplyStrategy(strategy), path)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(samplev1.GroupVersion.WithKind("MyChildResource").GroupKind(), child.Name, allErrs)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+)
+
+var _ = Describe("MyChildResource Webhook", func() {
+	var (
+		obj       *samplev1.MyChildResource
+		oldObj    *samplev1.MyChildResource
+		validator MyChildResourceCustomValidator
+		defaulter MyChildResourceCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &samplev1.MyChildResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        "webhook-child",
+				Annotations: map[string]string{controller.AnnotationStrategy: string(samplev1.ApplyStrategySSA)},
+			},
+			Spec: samplev1.MyChildResourceSpec{
+				FooMap:  map[string]string{"example.com/key": "value"},
+				FooList: []string{"a", "b"},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = MyChildResourceCustomValidator{Options: testOptions}
+		defaulter = MyChildResourceCustomDefaulter{}
+	})
+
+	Context("When creating MyChildResource under Defaulting Webhook", func() {
+		It("Should apply the defaults of the unset fields", func() {
+			obj.Spec = samplev1.MyChildResourceSpec{FooList: []string{"a"}}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec).To(Equal(samplev1.MyChildResourceSpec{
+				FooMap:          map[string]string{},
+				FooList:         []string{"a"},
+				FooValueDefault: samplev1.DefaultFooValue,
+			}))
+		})
+
+		It("Should keep the values that are set", func() {
+			obj.Spec.FooValueDefault = "explicit"
+			expected := obj.Spec.DeepCopy()
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec).To(Equal(*expected))
+		})
+	})
+
+	Context("When creating or updating MyChildResource under Validating Webhook", func() {
+		It("Should admit a valid child", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		DescribeTable("Should deny a child violating the business rules",
+			func(mutate func(*samplev1.MyChildResource), field, message string) {
+				mutate(obj)
+				_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue(), "%v", err)
+				Expect(err.Error()).To(ContainSubstring(field))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("duplicate list entry", func(c *samplev1.MyChildResource) { c.Spec.FooList = []string{"a", "b", "a"} },
+				"spec.fooList[2]", `Duplicate value: "a"`),
+			Entry("empty list entry", func(c *samplev1.MyChildResource) { c.Spec.FooList = []string{""} },
+				"spec.fooList[0]", "must not be empty"),
+			Entry("bad map key", func(c *samplev1.MyChildResource) { c.Spec.FooMap = map[string]string{"bad key": ""} },
+				"spec.fooMap[bad key]", "name part must consist of"),
+			Entry("unknown strategy annotation", func(c *samplev1.MyChildResource) {
+				c.Annotations[controller.AnnotationStrategy] = "Magic"
+			}, "metadata.annotations["+controller.AnnotationStrategy+"]", "Unsupported value"),
+		)
+	})
+
+	Context("When submitting MyChildResource to the API server", func() {
+		It("Should default the child like the reconciler does", func() {
+			obj.Name = "webhook-defaulted"
+			obj.Spec = samplev1.MyChildResourceSpec{}
+			Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, obj)
+
+			expected := samplev1.MyChildResourceSpec{}
+			expected.SetDefaults()
+			live := &samplev1.MyChildResource{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), live)).To(Succeed())
+			Expect(live.Spec).To(Equal(expected))
+		})
+
+		It("Should reject a child violating the business rules", func() {
+			obj.Spec.FooList = []string{"a", "a"}
+			err := k8sClient.Create(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "%v", err)
+			Expect(err.Error()).To(ContainSubstring(`spec.fooList[1]: Duplicate value: "a"`))
+		})
+	})
+})
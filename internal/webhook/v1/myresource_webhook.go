@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	samplev1 "k8s-controller.ad/api/v1"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var myresourcelog = logf.Log.WithName("myresource-resource")
+
+// SetupMyResourceWebhookWithManager registers the webhook for MyResource in the manager.
+func SetupMyResourceWebhookWithManager(mgr ctrl.Manager, opts Options) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&samplev1.MyResource{}).
+		WithValidator(&MyResourceCustomValidator{Options: opts}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-sample-k8s-controller-ad-v1-myresource,mutating=false,failurePolicy=fail,sideEffects=None,groups=sample.k8s-controller.ad,resources=myresources,verbs=create;update,versions=v1,name=vmyresource-v1.kb.io,admissionReviewVersions=v1
+
+// MyResourceCustomValidator struct is responsible for validating the MyResource resource
+// when it is created, updated, or deleted.
+//
+// It rejects child templates the reconciler would fail to render or apply:
+// invalid names, unknown or disabled strategies, duplicate children and
+// namespaces outside the namespace allow-list.
+type MyResourceCustomValidator struct {
+	Options
+}
+
+var _ webhook.CustomValidator = &MyResourceCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type MyResource.
+func (v *MyResourceCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	myresource, ok := obj.(*samplev1.MyResource)
+	if !ok {
+		return nil, fmt.Errorf("expected a MyResource object but got %T", obj)
+	}
+	myresourcelog.V(1).Info("Validation for MyResource upon creation", "name", myresource.GetName())
+
+	return nil, v.validate(myresource)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type MyResource.
+func (v *MyResourceCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	myresource, ok := newObj.(*samplev1.MyResource)
+	if !ok {
+		return nil, fmt.Errorf("expected a MyResource object for the newObj but got %T", newObj)
+	}
+	myresourcelog.V(1).Info("Validation for MyResource upon update", "name", myresource.GetName())
+
+	// Removing the finalizers of a parent being deleted must not be blocked
+	// by templates that became invalid, e.g. after an allow-list change.
+	if myresource.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	return nil, v.validate(myresource)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type MyResource.
+func (v *MyResourceCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the spec of parent.
+func (v *MyResourceCustomValidator) validate(parent *samplev1.MyResource) error {
+	specPath := field.NewPath("spec")
+	allErrs := v.validateStrategy(parent.Spec.Strategy, specPath.Child("strategy"))
+
+	seen := make(map[string]struct{}, len(parent.Spec.Children))
+	for i, tmpl := range parent.Spec.Children {
+		path := specPath.Child("children").Index(i)
+
+		allErrs = append(allErrs, invalidNames(path.Child("name"), tmpl.Name, validation.IsDNS1123Subdomain(tmpl.Name))...)
+		namespace := tmpl.Namespace
+		if namespace == "" {
+			namespace = parent.Namespace
+		} else {
+			allErrs = append(allErrs, invalidNames(path.Child("namespace"), namespace, validation.IsDNS1123Label(namespace))...)
+		}
+		if tmpl.Cluster != "" {
+			allErrs = append(allErrs, invalidNames(path.Child("cluster"), tmpl.Cluster, validation.IsDNS1123Subdomain(tmpl.Cluster))...)
+		}
+		if !v.NamespaceAllowList.Allowed(parent.Namespace, namespace) {
+			allErrs = append(allErrs, field.Forbidden(path.Child("namespace"),
+				fmt.Sprintf("children of resources in namespace %s may not be created in namespace %s", parent.Namespace, namespace)))
+		}
+
+		key := namespace + "/" + tmpl.Name
+		if tmpl.Cluster != "" {
+			key = tmpl.Cluster + ":" + key
+		}
+		if _, ok := seen[key]; ok {
+			allErrs = append(allErrs, field.Duplicate(path, key))
+		}
+		seen[key] = struct{}{}
+
+		allErrs = append(allErrs, metav1validation.ValidateLabels(tmpl.Labels, path.Child("labels"))...)
+		allErrs = append(allErrs, apivalidation.ValidateAnnotations(tmpl.Annotations, path.Child("annotations"))...)
+		allErrs = append(allErrs, v.validateStrategy(tmpl.Strategy, path.Child("strategy"))...)
+		allErrs = append(allErrs, validateChildSpec(&tmpl.Spec, path.Child("spec"))...)
+	}
+
+	if adoption := parent.Spec.Adoption; adoption != nil && adoption.Selector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(adoption.Selector,
+			metav1validation.LabelSelectorValidationOptions{}, specPath.Child("adoption", "selector"))...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(samplev1.GroupVersion.WithKind("MyResource").GroupKind(), parent.Name, allErrs)
+}
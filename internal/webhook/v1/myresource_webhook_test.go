@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	samplev2 "k8s-controller.ad/api/v2"
+	"k8s-controller.ad/internal/features"
+)
+
+var _ = Describe("MyResource Webhook", func() {
+	var (
+		obj       *samplev1.MyResource
+		oldObj    *samplev1.MyResource
+		validator MyResourceCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &samplev1.MyResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "webhook-parent"},
+			Spec: samplev1.MyResourceSpec{
+				Strategy: samplev1.ApplyStrategySSA,
+				Children: []samplev1.ChildTemplate{
+					{Name: "first", Spec: samplev1.MyChildResourceSpec{FooList: []string{"a", "b"}}},
+					{Name: "second", Namespace: "shared", Strategy: samplev1.ApplyStrategyPatch},
+				},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = MyResourceCustomValidator{Options: testOptions}
+	})
+
+	Context("When creating or updating MyResource under Validating Webhook", func() {
+		It("Should admit valid child templates", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		DescribeTable("Should deny invalid child templates",
+			func(mutate func(*samplev1.MyResource), field, message string) {
+				mutate(obj)
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(apierrors.IsInvalid(err)).To(BeTrue(), "%v", err)
+				Expect(err.Error()).To(ContainSubstring(field))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("bad name", func(r *samplev1.MyResource) { r.Spec.Children[0].Name = "Not_A_Name" },
+				"spec.children[0].name", "RFC 1123"),
+			Entry("bad namespace", func(r *samplev1.MyResource) { r.Spec.Children[0].Namespace = "Shared" },
+				"spec.children[0].namespace", "RFC 1123"),
+			Entry("unknown strategy", func(r *samplev1.MyResource) { r.Spec.Children[1].Strategy = "Magic" },
+				"spec.children[1].strategy", "Unsupported value"),
+			Entry("unknown parent strategy", func(r *samplev1.MyResource) { r.Spec.Strategy = "Magic" },
+				"spec.strategy", "Unsupported value"),
+			Entry("gated strategy", func(r *samplev1.MyResource) {
+				r.Spec.Children[0].Strategy = samplev1.ApplyStrategyThreeWayMerge
+			}, "spec.children[0].strategy", "requires the ThreeWayMerge feature gate"),
+			Entry("duplicate child", func(r *samplev1.MyResource) { r.Spec.Children[1] = r.Spec.Children[0] },
+				"spec.children[1]", `Duplicate value: "default/first"`),
+			Entry("duplicate child in the default namespace", func(r *samplev1.MyResource) {
+				r.Spec.Children[1].Name = "first"
+				r.Spec.Children[1].Namespace = "default"
+			}, "spec.children[1]", `Duplicate value: "default/first"`),
+			Entry("disallowed namespace", func(r *samplev1.MyResource) { r.Spec.Children[1].Namespace = "kube-system" },
+				"spec.children[1].namespace", "may not be created in namespace kube-system"),
+			Entry("bad label", func(r *samplev1.MyResource) {
+				r.Spec.Children[0].Labels = map[string]string{"bad key!": "value"}
+			}, "spec.children[0].labels", "bad key!"),
+			Entry("child business rule", func(r *samplev1.MyResource) {
+				r.Spec.Children[0].Spec.FooList = []string{"a", "a"}
+			}, "spec.children[0].spec.fooList[1]", `Duplicate value: "a"`),
+			Entry("bad adoption selector", func(r *samplev1.MyResource) {
+				r.Spec.Adoption = &samplev1.Adoption{
+					Policy: samplev1.AdoptionPolicyMatchingSelector,
+					Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "team", Operator: metav1.LabelSelectorOpIn},
+					}},
+				}
+			}, "spec.adoption.selector", "must be specified when `operator` is 'In' or 'NotIn'"),
+		)
+
+		It("Should admit the ThreeWayMerge strategy once its gate is enabled", func() {
+			gate := features.NewFeatureGate()
+			Expect(gate.Set("ThreeWayMerge=true")).To(Succeed())
+			validator.Features = gate
+			obj.Spec.Strategy = samplev1.ApplyStrategyThreeWayMerge
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should admit updates of a MyResource being deleted", func() {
+			obj.DeletionTimestamp = &metav1.Time{}
+			obj.Spec.Children[1].Namespace = "kube-system"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When submitting MyResource to the API server", func() {
+		It("Should reject invalid child templates with the reason", func() {
+			obj.Name = "webhook-invalid"
+			obj.Spec.Children[1].Namespace = "kube-system"
+			err := k8sClient.Create(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "%v", err)
+			Expect(err.Error()).To(ContainSubstring("may not be created in namespace kube-system"))
+		})
+
+		It("Should admit valid child templates and reject invalid updates", func() {
+			Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, obj)
+
+			obj.Spec.Children = append(obj.Spec.Children, obj.Spec.Children[0])
+			err := k8sClient.Update(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "%v", err)
+			Expect(err.Error()).To(ContainSubstring(`Duplicate value: "default/first"`))
+		})
+
+		It("Should convert between v1 and the v2 storage version", func() {
+			obj.Name = "webhook-conversion"
+			obj.Spec.Strategy = samplev1.ApplyStrategyPatch
+			obj.Spec.RevisionHistoryLimit = ptr.To[int32](3)
+			Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, obj)
+
+			stored := &samplev2.MyResource{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), stored)).To(Succeed())
+			Expect(stored.Spec.Strategy.Type).To(Equal(samplev2.ApplyStrategyPatch))
+			Expect(stored.Spec.Rollout.RevisionHistoryLimit).To(HaveValue(BeEquivalentTo(3)))
+			Expect(stored.Spec.Children).To(HaveLen(2))
+			Expect(stored.Spec.Children[1].Metadata.Namespace).To(Equal("shared"))
+			Expect(stored.Spec.Children[1].Strategy.Type).To(Equal(samplev2.ApplyStrategyPatch))
+
+			stored.Spec.Children[0].Spec.Foo = "from-v2"
+			Expect(k8sClient.Update(ctx, stored)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+			Expect(obj.Spec.Children[0].Spec.Foo).To(Equal("from-v2"))
+			Expect(obj.Spec.Strategy).To(Equal(samplev1.ApplyStrategyPatch))
+		})
+	})
+})
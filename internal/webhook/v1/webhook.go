@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1 contains the admission webhooks of the sample/v1 API.
+package v1
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/component-base/featuregate"
+
+	samplev1 "k8s-controller.ad/api/v1"
+	"k8s-controller.ad/internal/controller"
+	"k8s-controller.ad/internal/features"
+)
+
+// Options configures the webhooks with the settings of the controller, so that
+// objects the controller would refuse are rejected on admission instead.
+type Options struct {
+	// NamespaceAllowList restricts the namespaces children may be created in,
+	// as it does for the reconciler.
+	NamespaceAllowList controller.NamespaceAllowList
+	// Features gates experimental strategies and the child field protection.
+	// A nil gate uses the defaults.
+	Features featuregate.FeatureGate
+	// ControllerUsername is the user the controller authenticates as. The
+	// child field and deletion protections never reject its requests.
+	ControllerUsername string
+	// FieldProtectionBypassUsers may change the child fields applied by the
+	// controller regardless of the child field protection, e.g. break-glass
+	// service accounts as system:serviceaccount:<namespace>:<name>.
+	FieldProtectionBypassUsers []string
+}
+
+// validateStrategy checks that strategy is empty or known and enabled.
+func (o Options) validateStrategy(strategy samplev1.ApplyStrategy, path *field.Path) field.ErrorList {
+	if strategy == "" {
+		return nil
+	}
+	if !slices.Contains(samplev1.ApplyStrategies, strategy) {
+		supported := make([]string, 0, len(samplev1.ApplyStrategies))
+		for _, s := range samplev1.ApplyStrategies {
+			supported = append(supported, string(s))
+		}
+		return field.ErrorList{field.NotSupported(path, strategy, supported)}
+	}
+	if strategy == samplev1.ApplyStrategyThreeWayMerge && !features.Enabled(o.Features, features.ThreeWayMerge) {
+		return field.ErrorList{field.Forbidden(path,
+			fmt.Sprintf("the %s strategy requires the %s feature gate", strategy, features.ThreeWayMerge))}
+	}
+	return nil
+}
+
+// validateChildSpec checks the business rules of a MyChildResource spec: the
+// entries of fooList are unique and not empty and the keys of fooMap are
+// qualified names.
+func validateChildSpec(spec *samplev1.MyChildResourceSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := make(map[string]struct{}, len(spec.FooList))
+	for i, value := range spec.FooList {
+		switch _, duplicate := seen[value]; {
+		case value == "":
+			allErrs = append(allErrs, field.Required(path.Child("fooList").Index(i), "entries must not be empty"))
+		case duplicate:
+			allErrs = append(allErrs, field.Duplicate(path.Child("fooList").Index(i), value))
+		}
+		seen[value] = struct{}{}
+	}
+	for key := range spec.FooMap {
+		for _, msg := range validation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(path.Child("fooMap").Key(key), key, msg))
+		}
+	}
+	return allErrs
+}
+
+// invalidNames returns an error per message of a name validation function.
+func invalidNames(path *field.Path, name string, msgs []string) field.ErrorList {
+	allErrs := make(field.ErrorList, 0, len(msgs))
+	for _, msg := range msgs {
+		allErrs = append(allErrs, field.Invalid(path, name, msg))
+	}
+	return allErrs
+}
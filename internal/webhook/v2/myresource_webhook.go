@@ -0,0 +1,33 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v2 contains the admission and conversion webhooks of the sample/v2
+// API.
+package v2
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	samplev2 "k8s-controller.ad/api/v2"
+)
+
+// SetupMyResourceWebhookWithManager registers the conversion webhook for
+// MyResource in the manager. v2 is the hub every other version of MyResource
+// is converted through.
+func SetupMyResourceWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&samplev2.MyResource{}).
+		Complete()
+}
@@ -39,6 +39,9 @@ const serviceAccountName = "k8s-controller-simple-controller-manager"
 // metricsServiceName is the name of the metrics service of the project
 const metricsServiceName = "k8s-controller-simple-controller-manager-metrics-service"
 
+// sampleMyResource is the sample applied to populate the domain metrics
+const sampleMyResource = "config/samples/sample_v1_myresource.yaml"
+
 // metricsRoleBindingName is the name of the RBAC that will be created to allow get the metrics data
 const metricsRoleBindingName = "k8s-controller-simple-metrics-binding"
 
@@ -78,6 +81,10 @@ var _ = Describe("Manager", Ordered, func() {
 		cmd := exec.Command("kubectl", "delete", "pod", "curl-metrics", "-n", namespace)
 		_, _ = utils.Run(cmd)
 
+		By("cleaning up the sample MyResource")
+		cmd = exec.Command("kubectl", "delete", "-f", sampleMyResource, "-n", namespace, "--ignore-not-found")
+		_, _ = utils.Run(cmd)
+
 		By("undeploying the controller-manager")
 		cmd = exec.Command("make", "undeploy")
 		_, _ = utils.Run(cmd)
@@ -213,6 +220,22 @@ var _ = Describe("Manager", Ordered, func() {
 			}
 			Eventually(verifyMetricsServerStarted).Should(Succeed())
 
+			By("applying the sample MyResource so that the domain metrics are populated")
+			cmd = exec.Command("kubectl", "apply", "-f", sampleMyResource, "-n", namespace)
+			_, err = utils.Run(cmd)
+			Expect(err).NotTo(HaveOccurred(), "Failed to apply the sample MyResource")
+
+			By("waiting for the sample MyResource to be ready")
+			verifySampleReady := func(g Gomega) {
+				cmd := exec.Command("kubectl", "get", "myresources.sample.k8s-controller.ad", "myresource-sample",
+					"-o", `jsonpath={.status.conditions[?(@.type=="Ready")].status}`,
+					"-n", namespace)
+				output, err := utils.Run(cmd)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(output).To(Equal("True"), "sample MyResource is not ready")
+			}
+			Eventually(verifySampleReady).Should(Succeed())
+
 			By("creating the curl-metrics pod to access the metrics endpoint")
 			cmd = exec.Command("kubectl", "run", "curl-metrics", "--restart=Never",
 				"--namespace", namespace,
@@ -259,6 +282,72 @@ var _ = Describe("Manager", Ordered, func() {
 			Expect(metricsOutput).To(ContainSubstring(
 				"controller_runtime_reconcile_total",
 			))
+
+			By("checking the domain metrics of the controller")
+			for _, metric := range []string{
+				"myresource_controller_events_total",
+				"myresource_child_apply_duration_seconds",
+				"myresource_child_drifted_fields",
+				"myresource_child_apply_conflicts_total",
+				"myresource_api_requests_total",
+				"myresource_api_request_bytes_total",
+				"myresource_children",
+				`myresource_parents{ready="True"}`,
+			} {
+				Expect(metricsOutput).To(ContainSubstring(metric))
+			}
+		})
+
+		It("should provisioned cert-manager", func() {
+			By("validating that cert-manager has the certificate Secret")
+			verifyCertManager := func(g Gomega) {
+				cmd := exec.Command("kubectl", "get", "secrets", "webhook-server-cert", "-n", namespace)
+				_, err := utils.Run(cmd)
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			Eventually(verifyCertManager).Should(Succeed())
+		})
+
+		It("should have CA injection for validating webhooks", func() {
+			By("checking CA injection for validating webhooks")
+			verifyCAInjection := func(g Gomega) {
+				cmd := exec.Command("kubectl", "get",
+					"validatingwebhookconfigurations.admissionregistration.k8s.io",
+					"k8s-controller-simple-validating-webhook-configuration",
+					"-o", "go-template={{ range .webhooks }}{{ .clientConfig.caBundle }}{{ end }}")
+				vwhOutput, err := utils.Run(cmd)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(vwhOutput)).To(BeNumerically(">", 10))
+			}
+			Eventually(verifyCAInjection).Should(Succeed())
+		})
+
+		It("should have CA injection for mutating webhooks", func() {
+			By("checking CA injection for mutating webhooks")
+			verifyCAInjection := func(g Gomega) {
+				cmd := exec.Command("kubectl", "get",
+					"mutatingwebhookconfigurations.admissionregistration.k8s.io",
+					"k8s-controller-simple-mutating-webhook-configuration",
+					"-o", "go-template={{ range .webhooks }}{{ .clientConfig.caBundle }}{{ end }}")
+				mwhOutput, err := utils.Run(cmd)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(mwhOutput)).To(BeNumerically(">", 10))
+			}
+			Eventually(verifyCAInjection).Should(Succeed())
+		})
+
+		It("should have CA injection for MyResource conversion webhooks", func() {
+			By("checking CA injection for MyResource conversion webhooks")
+			verifyCAInjection := func(g Gomega) {
+				cmd := exec.Command("kubectl", "get",
+					"customresourcedefinitions.apiextensions.k8s.io",
+					"myresources.sample.k8s-controller.ad",
+					"-o", "go-template={{ .spec.conversion.webhook.clientConfig.caBundle }}")
+				crdOutput, err := utils.Run(cmd)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(crdOutput)).To(BeNumerically(">", 10))
+			}
+			Eventually(verifyCAInjection).Should(Succeed())
 		})
 
 		// +kubebuilder:scaffold:e2e-webhooks-checks